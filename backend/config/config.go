@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+
+	"Wallet/backend/pkg/observability"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -18,6 +21,104 @@ type Config struct {
 	Environment   string
 	TelegramToken string
 	BaseURL       string
+
+	// DAO governance defaults, used by DAOHandler.CreateProposal when a
+	// request doesn't specify its own Quorum/Threshold/VotingPeriod, and by
+	// CastVote to resolve the EIP-712 signing chain and the ERC20 governance
+	// token whose balance is snapshotted for VotePower.
+	ChainID                int64
+	GovernanceTokenAddress string
+	DAOQuorum              float64
+	DAOThreshold           float64
+	DAOVotingPeriodSeconds int64
+
+	// RiskModelPath, when set, points at a local ONNX model file that
+	// AIService loads instead of the built-in rules-based RiskScorer.
+	RiskModelPath string
+
+	// TelegramAuthTTLSeconds bounds how old a Telegram Login Widget or Mini
+	// App auth_date may be when verified by services.VerifyTelegramLoginWidget
+	// / VerifyTelegramMiniApp.
+	TelegramAuthTTLSeconds int64
+
+	// SMTP settings used by services.EmailNotifier to deliver "email"
+	// NotificationChannel alerts.
+	SMTPHost     string
+	SMTPPort     int64
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// TelegramWebhookIPAllowlist additionally restricts /telegram/webhook to
+	// Telegram's published CIDR ranges. Off by default, since it breaks
+	// behind reverse proxies that don't forward the true client IP.
+	TelegramWebhookIPAllowlist bool
+
+	// MTLSEnabled starts a second listener on MTLSPort that requires a
+	// client certificate issued via POST /api/auth/civic/device-cert,
+	// verified by services.CivicAuthService.VerifyPeerCertificate. Off by
+	// default since this service normally sits behind a reverse proxy
+	// (Render/Vercel) that terminates TLS itself.
+	MTLSEnabled bool
+	MTLSPort    string
+
+	// DatabaseDriver selects which storage.Storage backend InitStorage
+	// builds: "postgres" and "sqlite" both go through storage.GormStorage,
+	// sharing DatabaseURL; "mongo" builds a storage.MongoStorage against
+	// MongoURL/MongoDatabase instead.
+	DatabaseDriver string
+	MongoURL       string
+	MongoDatabase  string
+
+	// DBMaxIdleConns/DBMaxOpenConns are applied to the pool InitDB opens and
+	// re-applied in place by DBPool.Reload when they change, so tuning pool
+	// size doesn't require a restart.
+	DBMaxIdleConns int
+	DBMaxOpenConns int
+
+	// Civic* configure services.NewCivicAuthService's gateway client.
+	// CivicChainId reuses ChainID rather than duplicating it, since Civic
+	// and DAO governance target the same chain in this deployment.
+	CivicGatekeeperNetwork string
+	CivicAPIKey            string
+	CivicStage             string // "prod" or "preprod"
+
+	// LogLevel controls logging.Default()'s verbosity: "debug", "info",
+	// "warn", or "error". Applied via logging.SetLevel at startup and on
+	// every config.Watcher reload.
+	LogLevel string
+
+	// MetricsPort serves observability.Default()'s Prometheus registry on
+	// its own listener (see main.go), separate from ServerPort so a scraper
+	// doesn't need a route carved out of the main API's CORS/auth chain.
+	MetricsPort string
+	// OTLPEndpoint is the OTel collector gRPC address spans are exported to.
+	// Empty disables tracing (see observability.InitTracing).
+	OTLPEndpoint string
+	// TracingSampleRate is the fraction of traces sampled, in [0, 1].
+	TracingSampleRate float64
+
+	// GeoIPDBPath points at a MaxMind GeoLite2 database file. Empty disables
+	// GeoIP-based checks (see services.NewGeoIPLookup).
+	GeoIPDBPath string
+
+	// GovernorPrivateKey is the dao.ProposalExecutor hot wallet's signing
+	// key, loaded the same simple way as JWTSecret (a raw hex env value, no
+	// file/KMS reference). Empty disables the execution engine entirely -
+	// see routes.SetupMainRouter.
+	GovernorPrivateKey string
+	// DAOExecutorChains is a JSON object mapping a chain ID string to its
+	// dao.ChainConfig (RPC URL + governance contract address), parsed via
+	// dao.ParseChainConfigs.
+	DAOExecutorChains string
+	// DAOExecutorPollIntervalSeconds is how often dao.ProposalExecutor polls
+	// for passed proposals to execute.
+	DAOExecutorPollIntervalSeconds int64
+	// DAOExecutorDryRun, when true (the default), makes dao.ProposalExecutor
+	// simulate every execution via eth_call without ever broadcasting a
+	// transaction - a deployment opts into real on-chain submission
+	// explicitly rather than as a side effect of setting GovernorPrivateKey.
+	DAOExecutorDryRun bool
 }
 
 // LoadConfig loads configuration from .env file and environment variables
@@ -40,6 +141,51 @@ func LoadConfig() (*Config, error) {
 		Environment:   getEnv("ENVIRONMENT", "production"),
 		TelegramToken: getEnv("TELEGRAM_TOKEN", ""),
 		BaseURL:       getEnv("BASE_URL", "https://api.unhackablewallet.com"),
+
+		ChainID:                getEnvInt64("CHAIN_ID", 11155111), // Sepolia testnet
+		GovernanceTokenAddress: getEnv("GOVERNANCE_TOKEN_ADDRESS", ""),
+		DAOQuorum:              getEnvFloat("DAO_QUORUM", 1000),
+		DAOThreshold:           getEnvFloat("DAO_THRESHOLD", 0.5),
+		DAOVotingPeriodSeconds: getEnvInt64("DAO_VOTING_PERIOD_SECONDS", 7*24*60*60),
+
+		RiskModelPath: getEnv("RISK_MODEL_PATH", ""),
+
+		TelegramAuthTTLSeconds: getEnvInt64("TELEGRAM_AUTH_TTL_SECONDS", 60),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt64("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "alerts@unhackablewallet.com"),
+
+		TelegramWebhookIPAllowlist: getEnvBool("TELEGRAM_WEBHOOK_IP_ALLOWLIST", false),
+
+		MTLSEnabled: getEnvBool("MTLS_ENABLED", false),
+		MTLSPort:    getEnv("MTLS_PORT", "8443"),
+
+		DatabaseDriver: getEnv("DATABASE_DRIVER", "postgres"),
+		MongoURL:       getEnv("MONGO_URL", ""),
+		MongoDatabase:  getEnv("MONGO_DATABASE", "wallet"),
+
+		DBMaxIdleConns: int(getEnvInt64("DB_MAX_IDLE_CONNS", 10)),
+		DBMaxOpenConns: int(getEnvInt64("DB_MAX_OPEN_CONNS", 100)),
+
+		CivicGatekeeperNetwork: getEnv("CIVIC_GATEKEEPER_NETWORK", ""),
+		CivicAPIKey:            getEnv("CIVIC_API_KEY", ""),
+		CivicStage:             getEnv("CIVIC_STAGE", ""),
+
+		LogLevel: getEnv("LOG_LEVEL", "info"),
+
+		MetricsPort:       getEnv("METRICS_PORT", "9090"),
+		OTLPEndpoint:      getEnv("OTLP_ENDPOINT", ""),
+		TracingSampleRate: getEnvFloat("TRACING_SAMPLE_RATE", 0.1),
+
+		GeoIPDBPath: getEnv("GEOIP_DB_PATH", ""),
+
+		GovernorPrivateKey:             getEnv("GOVERNOR_PRIVKEY", ""),
+		DAOExecutorChains:              getEnv("DAO_EXECUTOR_CHAINS", ""),
+		DAOExecutorPollIntervalSeconds: getEnvInt64("DAO_EXECUTOR_POLL_INTERVAL_SECONDS", 60),
+		DAOExecutorDryRun:              getEnvBool("DAO_EXECUTOR_DRY_RUN", true),
 	}
 
 	// Validate configuration
@@ -72,14 +218,18 @@ func InitDB(cfg *Config) (*gorm.DB, error) {
 	}
 
 	// Set reasonable pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
 
 	// Verify connection
 	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if err := observability.InstrumentGORM(db); err != nil {
+		return nil, fmt.Errorf("failed to instrument database with tracing callbacks: %w", err)
+	}
+
 	return db, nil
 }
 
@@ -90,3 +240,89 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt64 gets an environment variable as an int64 or returns the default value
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvFloat gets an environment variable as a float64 or returns the default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool gets an environment variable as a bool or returns the default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// redactSecret replaces a non-empty secret with a fixed placeholder, so
+// Redacted() can report that a value is set without leaking it.
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***redacted***"
+}
+
+// Redacted returns c's fields as a JSON-friendly map with every
+// secret/credential-bearing field masked, for the debug config snapshot
+// endpoint (see routes.SetupMainRouter's "/admin/debug/config" route).
+func (c *Config) Redacted() map[string]interface{} {
+	return map[string]interface{}{
+		"serverPort":                 c.ServerPort,
+		"environment":                c.Environment,
+		"baseURL":                    c.BaseURL,
+		"mlModelURL":                 c.MLModelURL,
+		"riskModelPath":              c.RiskModelPath,
+		"chainID":                    c.ChainID,
+		"governanceTokenAddress":     c.GovernanceTokenAddress,
+		"daoQuorum":                  c.DAOQuorum,
+		"daoThreshold":               c.DAOThreshold,
+		"daoVotingPeriodSeconds":     c.DAOVotingPeriodSeconds,
+		"telegramAuthTTLSeconds":     c.TelegramAuthTTLSeconds,
+		"telegramWebhookIPAllowlist": c.TelegramWebhookIPAllowlist,
+		"smtpHost":                   c.SMTPHost,
+		"smtpPort":                   c.SMTPPort,
+		"smtpFrom":                   c.SMTPFrom,
+		"mtlsEnabled":                c.MTLSEnabled,
+		"mtlsPort":                   c.MTLSPort,
+		"databaseDriver":             c.DatabaseDriver,
+		"mongoDatabase":              c.MongoDatabase,
+		"dbMaxIdleConns":             c.DBMaxIdleConns,
+		"dbMaxOpenConns":             c.DBMaxOpenConns,
+		"civicGatekeeperNetwork":     c.CivicGatekeeperNetwork,
+		"civicStage":                 c.CivicStage,
+		"logLevel":                   c.LogLevel,
+		"metricsPort":                c.MetricsPort,
+		"otlpEndpoint":               c.OTLPEndpoint,
+		"tracingSampleRate":          c.TracingSampleRate,
+		"geoIPDBPath":                c.GeoIPDBPath,
+		"daoExecutorChains":          c.DAOExecutorChains,
+		"daoExecutorPollIntervalSec": c.DAOExecutorPollIntervalSeconds,
+		"daoExecutorDryRun":          c.DAOExecutorDryRun,
+
+		"databaseURL":        redactSecret(c.DatabaseURL),
+		"jwtSecret":          redactSecret(c.JWTSecret),
+		"telegramToken":      redactSecret(c.TelegramToken),
+		"smtpPassword":       redactSecret(c.SMTPPassword),
+		"civicAPIKey":        redactSecret(c.CivicAPIKey),
+		"mongoURL":           redactSecret(c.MongoURL),
+		"governorPrivateKey": redactSecret(c.GovernorPrivateKey),
+	}
+}
@@ -26,6 +26,16 @@ func InitializeDatabase(db *gorm.DB) error {
 		&models.Recovery{},
 		&models.TelegramMapping{},
 		&models.Config{},
+		&models.AddressReputation{},
+		&models.Nonce{},
+		&models.NotificationChannel{},
+		&models.NotificationDeadLetter{},
+		&models.PendingAction{},
+		&models.AddressAllowlistEntry{},
+		&models.ChatConversationState{},
+		&models.ProcessedTelegramUpdate{},
+		&models.Rule{},
+		&models.DeviceCredential{},
 	)
 
 	if err != nil {
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dbDrainGracePeriod is how long DBPool.Reload keeps a replaced *gorm.DB's
+// underlying connection open after swapping it out, so queries already
+// in flight against it can finish before it's closed.
+const dbDrainGracePeriod = 5 * time.Second
+
+// DBPool wraps the *gorm.DB InitDB opens so it can be reloaded in place:
+// a DatabaseURL change opens and verifies a new connection before swapping
+// it in and gracefully draining the old one; a pool-size-only change is
+// applied directly to the existing connection.
+//
+// DB() always returns the current connection, but components that were
+// handed a *gorm.DB directly at startup (every handler constructed by
+// routes.SetupMainRouter does this today) keep that original pointer - a
+// DatabaseURL reload only takes effect for callers that go through DB()
+// afterward. Migrating every handler onto DBPool.DB() is future work; see
+// the storage package's doc comment for the precedent of not doing that in
+// one pass.
+type DBPool struct {
+	mu  sync.RWMutex
+	db  *gorm.DB
+	cfg *Config
+}
+
+// NewDBPool wraps an already-opened *gorm.DB, as returned by InitDB.
+func NewDBPool(db *gorm.DB, cfg *Config) *DBPool {
+	return &DBPool{db: db, cfg: cfg}
+}
+
+// DB returns the pool's current connection.
+func (p *DBPool) DB() *gorm.DB {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db
+}
+
+// Reload applies cfg to the pool. If DatabaseURL is unchanged, the pool's
+// existing connection has its idle/open limits re-applied in place - since
+// every existing caller shares this same *gorm.DB, that takes effect
+// immediately everywhere. If DatabaseURL changed, a new connection is
+// opened and pinged before being swapped in; the old connection is closed
+// after dbDrainGracePeriod rather than immediately.
+func (p *DBPool) Reload(cfg *Config) error {
+	p.mu.Lock()
+	current := p.db
+	sameURL := cfg.DatabaseURL == p.cfg.DatabaseURL
+	p.mu.Unlock()
+
+	if sameURL {
+		sqlDB, err := current.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database instance: %w", err)
+		}
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+
+		p.mu.Lock()
+		p.cfg = cfg
+		p.mu.Unlock()
+		return nil
+	}
+
+	newDB, err := InitDB(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open reloaded database connection: %w", err)
+	}
+
+	p.mu.Lock()
+	oldDB := p.db
+	p.db = newDB
+	p.cfg = cfg
+	p.mu.Unlock()
+
+	go func() {
+		time.Sleep(dbDrainGracePeriod)
+		sqlDB, err := oldDB.DB()
+		if err != nil {
+			log.Printf("config: failed to get old database instance for draining: %v", err)
+			return
+		}
+		if err := sqlDB.Close(); err != nil {
+			log.Printf("config: failed to close drained database connection: %v", err)
+		}
+	}()
+
+	return nil
+}
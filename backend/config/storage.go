@@ -0,0 +1,37 @@
+package config
+
+import (
+	"Wallet/backend/storage"
+	"fmt"
+)
+
+// InitStorage builds a storage.Storage for cfg.DatabaseDriver ("mongo",
+// "sqlite", or Postgres for anything else). Nothing calls it at startup:
+// routes.SetupMainRouter wraps the main *gorm.DB with storage.NewGormStorage
+// for services.CivicAuthService and dao.ProposalExecutor, so
+// cfg.DatabaseDriver does not change the backend any running service uses.
+// InitDB/InitializeDatabase are likewise unaffected by cfg.DatabaseDriver.
+func InitStorage(cfg *Config) (*storage.Storage, error) {
+	switch cfg.DatabaseDriver {
+	case "mongo":
+		ms, err := storage.NewMongoStorage(cfg.MongoURL, cfg.MongoDatabase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize mongo storage: %w", err)
+		}
+		return &storage.Storage{Users: ms, Sessions: ms, DAO: ms, VerificationLogs: ms}, nil
+
+	case "sqlite":
+		gs, err := storage.NewSQLiteStorage(cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize sqlite storage: %w", err)
+		}
+		return &storage.Storage{Users: gs, Sessions: gs, DAO: gs, VerificationLogs: gs}, nil
+
+	default:
+		gs, err := storage.NewPostgresStorage(cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize postgres storage: %w", err)
+		}
+		return &storage.Storage{Users: gs, Sessions: gs, DAO: gs, VerificationLogs: gs}, nil
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"Wallet/backend/models"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// upgradeConfigKey mirrors handlers.upgradeConfigKey; kept in sync manually
+// since the two packages don't share a constants file.
+const upgradeConfigKey = "queued_upgrade"
+
+// QueuedUpgrade is the parsed form of the "upgrade" proposal payload most
+// recently written to the Config table by DAOHandler.ExecuteProposal or
+// DAOHandler.QueueUpgrade.
+type QueuedUpgrade struct {
+	TargetVersion   string
+	ActivationBlock uint64
+}
+
+// ReadQueuedUpgrade loads the most recently queued upgrade, if any. A
+// gorm.ErrRecordNotFound means no upgrade has ever been queued.
+func ReadQueuedUpgrade(db *gorm.DB) (*QueuedUpgrade, error) {
+	var row models.Config
+	if err := db.Where("key = ?", upgradeConfigKey).First(&row).Error; err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		TargetVersion   string `json:"targetVersion"`
+		ActivationBlock uint64 `json:"activationBlock"`
+	}
+	if err := json.Unmarshal([]byte(row.Value), &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse queued upgrade: %w", err)
+	}
+
+	return &QueuedUpgrade{TargetVersion: payload.TargetVersion, ActivationBlock: payload.ActivationBlock}, nil
+}
+
+// RefuseIfTooOld compares runningVersion against a queued upgrade's
+// TargetVersion and returns an error if they differ. Exact-match comparison
+// is used rather than semver ordering, since TargetVersion is
+// operator-defined and not guaranteed to be semver; as a result a binary
+// newer than TargetVersion is refused just like an older one. The queued
+// ActivationBlock is not consulted.
+func RefuseIfTooOld(db *gorm.DB, runningVersion string) error {
+	upgrade, err := ReadQueuedUpgrade(db)
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if upgrade.TargetVersion != "" && upgrade.TargetVersion != runningVersion {
+		return fmt.Errorf("a DAO upgrade proposal activated target version %q, but this binary reports %q; refusing to start", upgrade.TargetVersion, runningVersion)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/joho/godotenv"
+)
+
+// watchedFiles are observed for changes by Watcher. config.yaml doesn't
+// exist yet in this repo (all configuration comes from the environment/.env
+// via LoadConfig), but is watched in preparation for settings that don't fit
+// the flat env-var model; editing either file triggers a reload today.
+var watchedFiles = []string{".env", "config.yaml"}
+
+// reloadDebounce coalesces the burst of fsnotify events a single `save`
+// produces (many editors write a temp file then rename it over the
+// original) into one reload.
+const reloadDebounce = 300 * time.Millisecond
+
+// updatesBufferSize bounds the update channel LoadConfigWithWatcher returns.
+// Sized the same way services.WebsocketHub's per-client send channel is:
+// small, with the oldest pending update dropped rather than blocking the
+// watcher goroutine if the consumer falls behind. Only the latest config
+// ever matters, so dropping a stale intermediate snapshot is harmless.
+const updatesBufferSize = 1
+
+// ConfigSnapshot pairs a loaded *Config with the metadata a debug endpoint
+// needs to report what the running process currently sees.
+type ConfigSnapshot struct {
+	Config   *Config
+	Version  int
+	LoadedAt time.Time
+}
+
+// Watcher watches .env/config.yaml for changes and reloads the process's
+// Config, validating the new value before anything observes it. Validation
+// failure (e.g. DATABASE_URL or JWT_SECRET reloaded empty) is logged and the
+// previous, still-valid snapshot is kept.
+type Watcher struct {
+	mu      sync.RWMutex
+	current *ConfigSnapshot
+	fsw     *fsnotify.Watcher
+}
+
+// LoadConfigWithWatcher loads the initial Config the same way LoadConfig
+// does, then starts a background Watcher that emits every subsequently
+// reloaded Config on the returned channel until ctx is canceled. Callers
+// that don't need hot reload should keep using plain LoadConfig.
+func LoadConfigWithWatcher(ctx context.Context) (*Config, <-chan *Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range watchedFiles {
+		if abs, err := filepath.Abs(name); err == nil {
+			// Best-effort: config.yaml usually doesn't exist yet, and
+			// fsnotify can't watch a file that isn't there. .env being
+			// missing is likewise fine - env vars alone are a valid config
+			// source and just won't support hot reload.
+			_ = fsw.Add(abs)
+		}
+	}
+
+	w := &Watcher{
+		current: &ConfigSnapshot{Config: cfg, Version: 1, LoadedAt: time.Now()},
+		fsw:     fsw,
+	}
+
+	activeWatcher.Store(w)
+
+	updates := make(chan *Config, updatesBufferSize)
+	go w.run(ctx, updates)
+
+	return cfg, updates, nil
+}
+
+// Snapshot returns the most recently loaded ConfigSnapshot, for a debug
+// endpoint to report.
+func (w *Watcher) Snapshot() *ConfigSnapshot {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// activeWatcher holds the most recently started Watcher, so a debug
+// endpoint can report CurrentSnapshot() without SetupMainRouter needing its
+// own reference threaded through. Only one Watcher is expected to run per
+// process.
+var activeWatcher atomic.Pointer[Watcher]
+
+// CurrentSnapshot returns the active Watcher's most recent ConfigSnapshot,
+// or nil if LoadConfigWithWatcher hasn't been called (e.g. the process
+// started with plain LoadConfig and hot reload is disabled).
+func CurrentSnapshot() *ConfigSnapshot {
+	w := activeWatcher.Load()
+	if w == nil {
+		return nil
+	}
+	return w.Snapshot()
+}
+
+func (w *Watcher) run(ctx context.Context, out chan *Config) {
+	defer w.fsw.Close()
+
+	var debounce *time.Timer
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case _, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if debounce == nil {
+				debounce = time.NewTimer(reloadDebounce)
+			} else {
+				if !debounce.Stop() {
+					<-debounce.C
+				}
+				debounce.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("config watcher: fsnotify error: %v", err)
+
+		case <-debounceC(debounce):
+			debounce = nil
+			w.reload(out)
+		}
+	}
+}
+
+// debounceC returns t.C, or a nil channel (which blocks forever in a select)
+// while no debounce timer is running yet.
+func debounceC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
+func (w *Watcher) reload(out chan *Config) {
+	// LoadConfig's godotenv.Load never overrides a variable already set in
+	// the process (and every .env key was set by the initial load), so
+	// re-read .env with Overload first to pick up the edited values.
+	if os.Getenv("ENVIRONMENT") != "production" {
+		_ = godotenv.Overload()
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Printf("config watcher: reload rejected, keeping previous config: %v", err)
+		return
+	}
+
+	w.mu.Lock()
+	version := w.current.Version + 1
+	w.current = &ConfigSnapshot{Config: cfg, Version: version, LoadedAt: time.Now()}
+	w.mu.Unlock()
+
+	log.Printf("config watcher: reloaded config (version %d)", version)
+
+	select {
+	case out <- cfg:
+	default:
+		// Drop the stale pending update rather than block; the newest
+		// config always wins.
+		select {
+		case <-out:
+		default:
+		}
+		out <- cfg
+	}
+}
@@ -1,71 +1,188 @@
 package handlers
 
 import (
+	"Wallet/backend/config"
 	"Wallet/backend/models"
 	"Wallet/backend/services"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// validProposalTypes are the ProposalType values CreateProposal accepts.
+var validProposalTypes = map[string]bool{
+	"text":           true,
+	"param_change":   true,
+	"treasury_spend": true,
+	"upgrade":        true,
+}
+
+// validateProposalPayload checks that Payload parses against the shape
+// ProposalType expects. An empty ProposalType is treated as "text", which
+// never carries a payload.
+func validateProposalPayload(proposalType, payload string) error {
+	if proposalType == "" {
+		return nil
+	}
+	if !validProposalTypes[proposalType] {
+		return fmt.Errorf("unknown proposalType %q", proposalType)
+	}
+	if proposalType == "text" {
+		return nil
+	}
+	if payload == "" {
+		return fmt.Errorf("%s proposals require a payload", proposalType)
+	}
+
+	switch proposalType {
+	case "param_change":
+		var p models.ParamChangePayload
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			return fmt.Errorf("invalid param_change payload: %w", err)
+		}
+		if p.Key == "" {
+			return fmt.Errorf("param_change payload requires a non-empty key")
+		}
+		if !paramChangeAllowlist[p.Key] {
+			return fmt.Errorf("param_change proposals may not set config key %q", p.Key)
+		}
+	case "treasury_spend":
+		var p models.TreasurySpendPayload
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			return fmt.Errorf("invalid treasury_spend payload: %w", err)
+		}
+		if p.ToAddress == "" || p.Amount <= 0 {
+			return fmt.Errorf("treasury_spend payload requires toAddress and a positive amount")
+		}
+	case "upgrade":
+		var p models.UpgradePayload
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			return fmt.Errorf("invalid upgrade payload: %w", err)
+		}
+		if p.TargetVersion == "" {
+			return fmt.Errorf("upgrade payload requires a targetVersion")
+		}
+	}
+	return nil
+}
+
+// paramChangeAllowlist is the set of Config keys a passed param_change
+// proposal is permitted to write. It's empty today: every Config row this
+// codebase currently defines (services/telegram.go's webhookSecretConfigKey
+// and webhookURLConfigKey, "telegram_admin_chat", upgradeConfigKey below) is
+// security- or operator-sensitive, and ExecuteProposal is reachable by any
+// wallet-authenticated holder once a proposal passes quorum - none of those
+// keys may ever be settable by governance. Add a key here only once a
+// genuinely governance-tunable setting exists.
+var paramChangeAllowlist = map[string]bool{}
+
 // DAOHandler handles DAO voting endpoints
 type DAOHandler struct {
-	db *gorm.DB
+	db                *gorm.DB
 	blockchainService *services.BlockchainService
+	cfg               *config.Config
 }
 
 // NewDAOHandler creates a new DAO handler
-func NewDAOHandler(db *gorm.DB, blockchainService *services.BlockchainService) *DAOHandler {
+func NewDAOHandler(db *gorm.DB, blockchainService *services.BlockchainService, cfg *config.Config) *DAOHandler {
 	return &DAOHandler{
-		db: db,
+		db:                db,
 		blockchainService: blockchainService,
+		cfg:               cfg,
 	}
 }
 
-// CastVote records a DAO vote for a proposal
+// CastVote records a DAO vote for a proposal. The vote is only accepted if it
+// carries a valid EIP-712 signature over (proposalId, voteType, voter) that
+// recovers to the claimed VoterAddress; VotePower is then computed from the
+// governance token balance the voter held at the proposal's SnapshotBlock, not
+// from anything the client supplies.
 func (h *DAOHandler) CastVote(c *gin.Context) {
-	var vote models.DAOVote
-	if err := c.ShouldBindJSON(&vote); err != nil {
+	var req struct {
+		models.DAOVote
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid vote format"})
 		return
 	}
+	vote := req.DAOVote
 
-	// Set timestamp
-	vote.VotedAt = time.Now()
+	if vote.VoteType != "for" && vote.VoteType != "against" && vote.VoteType != "abstain" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "voteType must be one of: for, against, abstain"})
+		return
+	}
 
-	// Check if proposal exists
+	signer, err := services.RecoverVoteSigner(h.cfg.ChainID, services.VotePayload{
+		ProposalID: uint64(vote.ProposalID),
+		VoteType:   vote.VoteType,
+		Voter:      vote.VoterAddress,
+	}, vote.Signature)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid vote signature: " + err.Error()})
+		return
+	}
+	if !strings.EqualFold(signer, vote.VoterAddress) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Signature does not match voterAddress"})
+		return
+	}
+
+	// Check if proposal exists and is still open
 	var proposal models.DAOProposal
 	if result := h.db.First(&proposal, vote.ProposalID); result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Proposal not found"})
 		return
 	}
+	if proposal.Status != "active" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Proposal is no longer accepting votes"})
+		return
+	}
+	if time.Now().After(proposal.EndTime) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Voting period has ended"})
+		return
+	}
 
-	// Check if user already voted
-	var existingVote models.DAOVote
-	result := h.db.Where("proposal_id = ? AND voter_address = ?", vote.ProposalID, vote.VoterAddress).First(&existingVote)
-	if result.Error == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "You have already voted on this proposal"})
+	votePower, err := h.blockchainService.TokenBalanceAt(h.cfg.GovernanceTokenAddress, vote.VoterAddress, proposal.SnapshotBlock)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to read snapshot token balance: " + err.Error()})
 		return
 	}
+	if votePower <= 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "No voting power at proposal snapshot block"})
+		return
+	}
+
+	vote.VoterAddress = signer
+	vote.VotePower = votePower
+	vote.VotedAt = time.Now()
 
-	// Save vote to database
+	// DAOVote's (proposal_id, voter_address) uniqueIndex is what actually
+	// stops double voting; a prior check-then-act read here would still let
+	// two concurrent requests both pass the read and double-count power.
 	if err := h.db.Create(&vote).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vote"})
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already voted on this proposal"})
 		return
 	}
 
-	// Update proposal vote counts
-	if vote.VoteType == "for" {
+	// Update proposal power tallies and legacy vote counters
+	switch vote.VoteType {
+	case "for":
 		proposal.VotesFor++
-	} else if vote.VoteType == "against" {
+		proposal.ForPower += votePower
+	case "against":
 		proposal.VotesAgainst++
+		proposal.AgainstPower += votePower
+	case "abstain":
+		proposal.AbstainPower += votePower
 	}
 	h.db.Save(&proposal)
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Vote recorded successfully",
+		"message":  "Vote recorded successfully",
 		"proposal": proposal,
 	})
 }
@@ -73,7 +190,7 @@ func (h *DAOHandler) CastVote(c *gin.Context) {
 // GetProposals retrieves all active DAO proposals
 func (h *DAOHandler) GetProposals(c *gin.Context) {
 	var proposals []models.DAOProposal
-	
+
 	result := h.db.Find(&proposals)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch proposals"})
@@ -91,12 +208,52 @@ func (h *DAOHandler) CreateProposal(c *gin.Context) {
 		return
 	}
 
+	// RequireSignedRequest has already verified the EIP-712 signature over
+	// this request and put the recovered signer in authed_address; the
+	// proposal's creator is always the signer, never a client-supplied value.
+	authedAddress := c.MustGet("authed_address").(string)
+	if proposal.CreatorAddress != "" && !strings.EqualFold(proposal.CreatorAddress, authedAddress) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "creatorAddress does not match signed request"})
+		return
+	}
+	proposal.CreatorAddress = authedAddress
+
+	if proposal.ProposalType == "" {
+		proposal.ProposalType = "text"
+	}
+	if err := validateProposalPayload(proposal.ProposalType, proposal.Payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Snapshot the current chain head so VotePower can't be inflated by
+	// acquiring tokens after the proposal exists.
+	snapshotBlock, err := h.blockchainService.CurrentBlockNumber()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to read current block: " + err.Error()})
+		return
+	}
+
+	if proposal.Quorum <= 0 {
+		proposal.Quorum = h.cfg.DAOQuorum
+	}
+	if proposal.Threshold <= 0 {
+		proposal.Threshold = h.cfg.DAOThreshold
+	}
+	if proposal.VotingPeriod <= 0 {
+		proposal.VotingPeriod = h.cfg.DAOVotingPeriodSeconds
+	}
+	if proposal.ChainID <= 0 {
+		proposal.ChainID = h.cfg.ChainID
+	}
+
 	// Set default values
 	proposal.CreatedAt = time.Now()
 	proposal.Status = "active"
 	proposal.VotesFor = 0
 	proposal.VotesAgainst = 0
-	proposal.EndTime = time.Now().AddDate(0, 0, 7) // 7 days from now
+	proposal.SnapshotBlock = snapshotBlock
+	proposal.EndTime = proposal.CreatedAt.Add(time.Duration(proposal.VotingPeriod) * time.Second)
 
 	// Save proposal to database
 	if err := h.db.Create(&proposal).Error; err != nil {
@@ -105,7 +262,206 @@ func (h *DAOHandler) CreateProposal(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id": proposal.ID,
+		"id":      proposal.ID,
 		"message": "Proposal created successfully",
 	})
 }
+
+// TallyProposal finalizes a proposal whose EndTime has elapsed, flipping its
+// Status to "passed", "rejected" or "failed_quorum" based on the tallied
+// ForPower/AgainstPower/AbstainPower against Quorum and Threshold. It is safe
+// to call repeatedly: proposals that are already finalized, or still active,
+// are returned unchanged.
+func (h *DAOHandler) TallyProposal(c *gin.Context) {
+	id := c.Param("id")
+
+	var proposal models.DAOProposal
+	if result := h.db.First(&proposal, id); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Proposal not found"})
+		return
+	}
+
+	if proposal.Status == "active" && time.Now().After(proposal.EndTime) {
+		h.finalizeProposal(&proposal)
+		h.db.Save(&proposal)
+	}
+
+	totalPower := proposal.ForPower + proposal.AgainstPower + proposal.AbstainPower
+	quorumMet := totalPower >= proposal.Quorum
+
+	c.JSON(http.StatusOK, gin.H{
+		"proposal_id":   proposal.ID,
+		"status":        proposal.Status,
+		"for_power":     proposal.ForPower,
+		"against_power": proposal.AgainstPower,
+		"abstain_power": proposal.AbstainPower,
+		"quorum":        proposal.Quorum,
+		"quorum_met":    quorumMet,
+	})
+}
+
+// finalizeProposal applies the quorum/threshold rules to a proposal whose
+// voting period has ended. Callers must Save the proposal afterwards.
+func (h *DAOHandler) finalizeProposal(proposal *models.DAOProposal) {
+	totalPower := proposal.ForPower + proposal.AgainstPower + proposal.AbstainPower
+	if totalPower < proposal.Quorum {
+		proposal.Status = "failed_quorum"
+		return
+	}
+
+	decisivePower := proposal.ForPower + proposal.AgainstPower
+	if decisivePower == 0 || proposal.ForPower/decisivePower < proposal.Threshold {
+		proposal.Status = "rejected"
+		return
+	}
+
+	proposal.Status = "passed"
+}
+
+// TallyExpiredProposals scans for active proposals past their EndTime and
+// finalizes each one. It is invoked periodically by the background ticker
+// started in main.go so proposals don't sit "active" forever once voting
+// closes.
+func (h *DAOHandler) TallyExpiredProposals() error {
+	var expired []models.DAOProposal
+	if err := h.db.Where("status = ? AND end_time <= ?", "active", time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+
+	for i := range expired {
+		h.finalizeProposal(&expired[i])
+		if err := h.db.Save(&expired[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecuteProposal applies a passed proposal's Payload and marks it
+// "executed". It is a no-op for "text" proposals, since those carry no
+// executable payload.
+func (h *DAOHandler) ExecuteProposal(c *gin.Context) {
+	id := c.Param("id")
+
+	var proposal models.DAOProposal
+	if result := h.db.First(&proposal, id); result.Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Proposal not found"})
+		return
+	}
+	if proposal.Status != "passed" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only passed proposals can be executed"})
+		return
+	}
+
+	var execErr error
+	switch proposal.ProposalType {
+	case "param_change":
+		execErr = h.executeParamChange(proposal.Payload)
+	case "treasury_spend":
+		execErr = h.executeTreasurySpend(proposal.Payload)
+	case "upgrade":
+		execErr = h.QueueUpgradeFromPayload(proposal.Payload)
+	}
+	if execErr != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Execution failed: " + execErr.Error()})
+		return
+	}
+
+	proposal.Status = "executed"
+	if err := h.db.Save(&proposal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark proposal executed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Proposal executed successfully", "proposal": proposal})
+}
+
+// executeParamChange applies a param_change proposal's key/value update to
+// the Config table, refusing any key not in paramChangeAllowlist. Internal
+// callers that need to write a trusted, hardcoded key (e.g.
+// QueueUpgradeFromPayload) use writeConfig directly instead, since they
+// aren't writing an attacker-influenced key and shouldn't be gated by a
+// governance allowlist.
+func (h *DAOHandler) executeParamChange(payload string) error {
+	var p models.ParamChangePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return fmt.Errorf("invalid param_change payload: %w", err)
+	}
+	if !paramChangeAllowlist[p.Key] {
+		return fmt.Errorf("param_change proposals may not set config key %q", p.Key)
+	}
+	return writeConfig(h.db, p.Key, p.Value)
+}
+
+// writeConfig upserts key=value into the Config table inside a transaction.
+func writeConfig(db *gorm.DB, key, value string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var cfgRow models.Config
+		result := tx.Where("key = ?", key).First(&cfgRow)
+		if result.Error == gorm.ErrRecordNotFound {
+			return tx.Create(&models.Config{Key: key, Value: value}).Error
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		cfgRow.Value = value
+		return tx.Save(&cfgRow).Error
+	})
+}
+
+// executeTreasurySpend submits the payload's transfer as a signed multisig
+// transaction to the treasury contract via BlockchainService.
+func (h *DAOHandler) executeTreasurySpend(payload string) error {
+	var p models.TreasurySpendPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return fmt.Errorf("invalid treasury_spend payload: %w", err)
+	}
+
+	_, err := h.blockchainService.SubmitTreasuryTransaction(p.ToAddress, p.TokenAddress, p.Amount)
+	return err
+}
+
+// QueueUpgrade records a target version and activation block directly,
+// without requiring a full proposal lifecycle; used for emergency upgrades.
+func (h *DAOHandler) QueueUpgrade(c *gin.Context) {
+	var payload models.UpgradePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upgrade payload"})
+		return
+	}
+	if payload.TargetVersion == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "targetVersion is required"})
+		return
+	}
+
+	if err := h.QueueUpgradeFromPayload(mustMarshal(payload)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue upgrade: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Upgrade queued", "upgrade": payload})
+}
+
+// upgradeConfigKey is the Config row key the queued upgrade payload is
+// stored under. main.go reads it at startup, via config.RefuseIfTooOld, to
+// refuse booting a binary whose version differs from TargetVersion;
+// ActivationBlock is not checked there.
+const upgradeConfigKey = "queued_upgrade"
+
+// QueueUpgradeFromPayload persists an upgrade payload (already-marshaled
+// JSON) into the Config table under upgradeConfigKey.
+func (h *DAOHandler) QueueUpgradeFromPayload(payload string) error {
+	var p models.UpgradePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return fmt.Errorf("invalid upgrade payload: %w", err)
+	}
+	return writeConfig(h.db, upgradeConfigKey, payload)
+}
+
+// mustMarshal is a small helper for the cases above where the struct being
+// marshaled is always one we constructed ourselves and can never fail to
+// encode.
+func mustMarshal(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"Wallet/backend/models"
 	"Wallet/backend/services"
+	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"time"
@@ -11,11 +13,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// watchlistReputationThreshold is the AddressReputation.Score above which an
+// address is included in the "watchlist" rule variable.
+const watchlistReputationThreshold = 0.5
+
+// transactionVelocityWindow bounds the "velocity" rule variable to
+// transactions from the same sender within this window.
+const transactionVelocityWindow = 1 * time.Hour
+
 // FirewallHandler handles transaction firewall endpoints
 type FirewallHandler struct {
-	db              *gorm.DB
-	aiService       *services.AIService
-	telegramService *services.TelegramService
+	db                  *gorm.DB
+	aiService           *services.AIService
+	telegramService     *services.TelegramService
+	notificationService *services.NotificationService
+	eventBus            *services.EventBus
+	wsHub               *services.WebsocketHub
 }
 
 // NewFirewallHandler creates a new firewall handler
@@ -27,6 +40,26 @@ func NewFirewallHandler(db *gorm.DB, aiService *services.AIService, telegramServ
 	}
 }
 
+// SetNotificationService attaches a NotificationService so AnalyzeTransaction
+// can fan suspicious/blocked transaction alerts out to every channel a
+// wallet has registered, not just its linked Telegram chat.
+func (h *FirewallHandler) SetNotificationService(notificationService *services.NotificationService) {
+	h.notificationService = notificationService
+}
+
+// SetEventBus attaches an EventBus so AnalyzeTransaction can publish
+// TransactionAnalyzed/SecurityAlertRaised events for services.RuleEngine (and
+// any other subscriber) to act on.
+func (h *FirewallHandler) SetEventBus(eventBus *services.EventBus) {
+	h.eventBus = eventBus
+}
+
+// SetWebsocketHub attaches the hub backing GET /api/ws, purely so
+// GetAdminStats can surface its dropped-message backpressure counter.
+func (h *FirewallHandler) SetWebsocketHub(wsHub *services.WebsocketHub) {
+	h.wsHub = wsHub
+}
+
 // AnalyzeTransaction analyzes a transaction for potential threats
 func (h *FirewallHandler) AnalyzeTransaction(c *gin.Context) {
 	var tx models.Transaction
@@ -35,11 +68,28 @@ func (h *FirewallHandler) AnalyzeTransaction(c *gin.Context) {
 		return
 	}
 
+	status, risk, err := h.Analyze(tx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": status,
+		"risk":   risk,
+	})
+}
+
+// Analyze runs the full transaction-firewall pipeline (AI scoring,
+// persistence, event publishing, notification fan-out) without any
+// gin.Context dependency, so it can be driven both by the HTTP endpoint
+// above and by the "analyze" WebSocket JSON-RPC method via
+// services.WebsocketHub.SetAnalyzeFunc.
+func (h *FirewallHandler) Analyze(tx models.Transaction) (string, float64, error) {
 	// Call AI service to analyze transaction
 	risk, err := h.aiService.AnalyzeTransaction(tx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze transaction"})
-		return
+		return "", 0, fmt.Errorf("failed to analyze transaction: %w", err)
 	}
 
 	// Determine status based on risk score
@@ -52,6 +102,19 @@ func (h *FirewallHandler) AnalyzeTransaction(c *gin.Context) {
 
 	// Save transaction to database
 	tx.Risk = risk
+	tx.Status = status
+	if err := h.db.Create(&tx).Error; err != nil {
+		return "", 0, fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	if h.eventBus != nil {
+		h.eventBus.Publish(services.Event{
+			Type:          services.EventTransactionAnalyzed,
+			WalletAddress: tx.FromAddress,
+			Payload:       h.ruleContext(tx, risk),
+			Timestamp:     time.Now(),
+		})
+	}
 
 	// Send Telegram notification for suspicious or blocked transactions
 	if status != "safe" {
@@ -61,35 +124,86 @@ func (h *FirewallHandler) AnalyzeTransaction(c *gin.Context) {
 			description += " - " + tx.Metadata
 		}
 
-		alert := &models.SecurityAlert{
-			WalletID:  tx.FromAddress,
-			Type:      "suspicious_transaction",
-			Severity:  status,
-			Details:   description,
-			Timestamp: time.Now().Unix(),
-			Status:    "pending",
+		if h.eventBus != nil {
+			h.eventBus.Publish(services.Event{
+				Type:          services.EventSecurityAlertRaised,
+				WalletAddress: tx.FromAddress,
+				Payload: map[string]interface{}{
+					"type":     "suspicious_transaction",
+					"severity": status,
+					"details":  description,
+				},
+				Timestamp: time.Now(),
+			})
 		}
 
-		// Try to send Telegram notification (don't block if it fails)
-		go func() {
-			err := h.telegramService.NotifySecurityAlert(tx.FromAddress, alert)
-			if err != nil {
-				// Log the error but continue processing
-				log.Printf("Failed to send Telegram notification: %v", err)
+		if h.notificationService != nil {
+			// Dispatch fans this out to every channel (Telegram, Slack,
+			// Discord, webhook, email, n8n) the wallet has registered,
+			// concurrently and with its own retry/dead-letter handling, so
+			// we don't block the response on any of them.
+			go h.notificationService.Dispatch(context.Background(), tx.FromAddress, services.NotificationEvent{
+				Type:      "suspicious_transaction",
+				Severity:  status,
+				Title:     "Suspicious transaction detected",
+				Details:   description,
+				Timestamp: time.Now(),
+			})
+		} else {
+			alert := &models.SecurityAlert{
+				WalletID:  tx.FromAddress,
+				Type:      "suspicious_transaction",
+				Severity:  status,
+				Details:   description,
+				Timestamp: time.Now().Unix(),
+				Status:    "pending",
 			}
-		}()
+
+			// With no generic NotificationService attached, fall back to a
+			// Telegram alert with inline "allow once / block & report /
+			// always allow" buttons wired to tx.ID (don't block the response).
+			go func() {
+				if err := h.telegramService.NotifyTransactionAlert(tx.FromAddress, tx.ID, tx.ToAddress, alert); err != nil {
+					log.Printf("Failed to send Telegram notification: %v", err)
+				}
+			}()
+		}
 	}
 
-	tx.Status = status
-	if err := h.db.Create(&tx).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save transaction"})
-		return
+	return status, risk, nil
+}
+
+// ruleContext builds the services.RuleContext a Rule's Expression is
+// evaluated against for tx, matching the variable set documented by
+// services.SampleRuleContext.
+func (h *FirewallHandler) ruleContext(tx models.Transaction, risk float64) map[string]interface{} {
+	var velocity int64
+	h.db.Model(&models.Transaction{}).
+		Where("from_address = ? AND created_at > ?", tx.FromAddress, time.Now().Add(-transactionVelocityWindow)).
+		Count(&velocity)
+
+	var reportCount int64
+	h.db.Model(&models.Report{}).
+		Where("reported_address = ? AND status = ?", tx.ToAddress, "verified").
+		Count(&reportCount)
+
+	var watchlistEntries []models.AddressReputation
+	h.db.Where("score > ?", watchlistReputationThreshold).Find(&watchlistEntries)
+	watchlist := make([]string, len(watchlistEntries))
+	for i, entry := range watchlistEntries {
+		watchlist[i] = entry.Address
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": status,
-		"risk":   risk,
-	})
+	return map[string]interface{}{
+		"risk":           risk,
+		"value":          tx.Value,
+		"velocity":       float64(velocity),
+		"report_count":   float64(reportCount),
+		"to_address":     tx.ToAddress,
+		"from_address":   tx.FromAddress,
+		"watchlist":      watchlist,
+		"transaction_id": tx.ID,
+	}
 }
 
 // GetStats returns transaction security statistics
@@ -137,14 +251,15 @@ func (h *FirewallHandler) GetTransactions(c *gin.Context) {
 func (h *FirewallHandler) GetAdminStats(c *gin.Context) {
 	// Get various statistics
 	var stats struct {
-		TotalTransactions       int64 `json:"totalTransactions"`
-		BlockedTransactions     int64 `json:"blockedTransactions"`
-		SuspiciousTransactions  int64 `json:"suspiciousTransactions"`
-		SafeTransactions        int64 `json:"safeTransactions"`
-		TotalReports            int64 `json:"totalReports"`
-		VerifiedReports         int64 `json:"verifiedReports"`
-		UniqueAddressesReported int64 `json:"uniqueAddressesReported"`
-		LastDayTransactions     int64 `json:"lastDayTransactions"`
+		TotalTransactions        int64 `json:"totalTransactions"`
+		BlockedTransactions      int64 `json:"blockedTransactions"`
+		SuspiciousTransactions   int64 `json:"suspiciousTransactions"`
+		SafeTransactions         int64 `json:"safeTransactions"`
+		TotalReports             int64 `json:"totalReports"`
+		VerifiedReports          int64 `json:"verifiedReports"`
+		UniqueAddressesReported  int64 `json:"uniqueAddressesReported"`
+		LastDayTransactions      int64 `json:"lastDayTransactions"`
+		WebsocketDroppedMessages int64 `json:"websocketDroppedMessages"`
 	}
 
 	// Count different transaction types
@@ -164,6 +279,10 @@ func (h *FirewallHandler) GetAdminStats(c *gin.Context) {
 	yesterday := time.Now().Add(-24 * time.Hour)
 	h.db.Model(&models.Transaction{}).Where("created_at > ?", yesterday).Count(&stats.LastDayTransactions)
 
+	if h.wsHub != nil {
+		stats.WebsocketDroppedMessages = h.wsHub.DroppedMessageCount()
+	}
+
 	// Return all stats
 	c.JSON(http.StatusOK, stats)
 }
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"Wallet/backend/models"
+	"Wallet/backend/services"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// NotificationHandler manages a wallet's NotificationChannel subscriptions.
+type NotificationHandler struct {
+	db *gorm.DB
+}
+
+// NewNotificationHandler creates a new notification channel handler.
+func NewNotificationHandler(db *gorm.DB) *NotificationHandler {
+	return &NotificationHandler{db: db}
+}
+
+// urlTargetChannelTypes are the NotificationChannel types whose Target is a
+// URL the server itself will POST to (see services.WebhookNotifier and its
+// Slack/Discord/n8n siblings) - "telegram" carries a chat ID and "email" an
+// address, so neither goes through validateChannelTarget.
+var urlTargetChannelTypes = map[string]bool{
+	"webhook": true,
+	"slack":   true,
+	"discord": true,
+	"n8n":     true,
+}
+
+// validateChannelTarget rejects a URL-based channel.Target that would make
+// the server's outbound notification requests land on infrastructure a
+// wallet owner shouldn't be able to reach - the cloud metadata endpoint,
+// a cluster-internal service, or localhost. This is a best-effort check at
+// channel-creation time only; services.ssrfSafeClient (see
+// services/notifier_webhook.go) re-resolves and re-validates the target on
+// every send, since a hostname allowed here could later be repointed at a
+// disallowed address (DNS rebinding).
+func validateChannelTarget(channelType, target string) error {
+	if !urlTargetChannelTypes[channelType] {
+		return nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid target URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("target must be an http or https URL")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("target must include a host")
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target host: %w", err)
+	}
+	for _, ip := range ips {
+		if services.DisallowedTargetIP(ip) {
+			return fmt.Errorf("target resolves to a disallowed address: %s", ip)
+		}
+	}
+	return nil
+}
+
+// CreateChannel registers a new notification destination for the
+// authenticated wallet.
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var channel models.NotificationChannel
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid channel format"})
+		return
+	}
+	if channel.ChannelType == "" || channel.Target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "channelType and target are required"})
+		return
+	}
+	if err := validateChannelTarget(channel.ChannelType, channel.Target); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if channel.MinSeverity == "" {
+		channel.MinSeverity = "low"
+	}
+
+	channel.WalletAddress = address.(string)
+	channel.Enabled = true
+
+	if err := h.db.Create(&channel).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save notification channel"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, channel)
+}
+
+// GetChannels lists the authenticated wallet's registered notification channels.
+func (h *NotificationHandler) GetChannels(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var channels []models.NotificationChannel
+	if err := h.db.Where("wallet_address = ?", address.(string)).Find(&channels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notification channels"})
+		return
+	}
+
+	c.JSON(http.StatusOK, channels)
+}
+
+// DeleteChannel removes one of the authenticated wallet's notification channels.
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id := c.Param("id")
+	result := h.db.Where("id = ? AND wallet_address = ?", id, address.(string)).Delete(&models.NotificationChannel{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification channel"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification channel not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification channel deleted"})
+}
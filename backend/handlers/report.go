@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"Wallet/backend/models"
+	"Wallet/backend/services"
+	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +14,9 @@ import (
 
 // ReportHandler handles scam report endpoints
 type ReportHandler struct {
-	db *gorm.DB
+	db                  *gorm.DB
+	notificationService *services.NotificationService
+	eventBus            *services.EventBus
 }
 
 // NewReportHandler creates a new report handler
@@ -21,6 +26,20 @@ func NewReportHandler(db *gorm.DB) *ReportHandler {
 	}
 }
 
+// SetNotificationService attaches a NotificationService so CreateReport can
+// fan scam-report alerts out to every channel the reported-on wallet's
+// owner has registered.
+func (h *ReportHandler) SetNotificationService(notificationService *services.NotificationService) {
+	h.notificationService = notificationService
+}
+
+// SetEventBus attaches an EventBus so CreateReport can publish a
+// ReportCreated event for subscribers (e.g. a future rule type keyed on
+// report volume) to act on.
+func (h *ReportHandler) SetEventBus(eventBus *services.EventBus) {
+	h.eventBus = eventBus
+}
+
 // CreateReport creates a new scam report
 func (h *ReportHandler) CreateReport(c *gin.Context) {
 	var report models.Report
@@ -29,6 +48,16 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		return
 	}
 
+	// RequireSignedRequest has already verified the EIP-712 signature over
+	// this request and put the recovered signer in authed_address; the
+	// report's reporter is always the signer, never a client-supplied value.
+	authedAddress := c.MustGet("authed_address").(string)
+	if report.ReporterAddress != "" && !strings.EqualFold(report.ReporterAddress, authedAddress) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "reporterAddress does not match signed request"})
+		return
+	}
+	report.ReporterAddress = authedAddress
+
 	// Set default values
 	report.CreatedAt = time.Now()
 	report.Status = "pending"
@@ -39,8 +68,31 @@ func (h *ReportHandler) CreateReport(c *gin.Context) {
 		return
 	}
 
+	if h.notificationService != nil {
+		go h.notificationService.Dispatch(context.Background(), report.ReportedAddress, services.NotificationEvent{
+			Type:      "scam_report",
+			Severity:  "medium",
+			Title:     "Scam report filed",
+			Details:   report.Category + ": " + report.Description,
+			Timestamp: report.CreatedAt,
+		})
+	}
+
+	if h.eventBus != nil {
+		h.eventBus.Publish(services.Event{
+			Type:          services.EventReportCreated,
+			WalletAddress: report.ReportedAddress,
+			Payload: map[string]interface{}{
+				"reporter_address": report.ReporterAddress,
+				"category":         report.Category,
+				"description":      report.Description,
+			},
+			Timestamp: report.CreatedAt,
+		})
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"id": report.ID,
+		"id":      report.ID,
 		"message": "Report submitted successfully",
 	})
 }
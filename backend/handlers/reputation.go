@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"Wallet/backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReputationHandler exposes the address reputation subsystem.
+type ReputationHandler struct {
+	reputationService *services.ReputationService
+}
+
+// NewReputationHandler creates a new reputation handler.
+func NewReputationHandler(reputationService *services.ReputationService) *ReputationHandler {
+	return &ReputationHandler{reputationService: reputationService}
+}
+
+// GetReputation returns the current decayed reputation score for an address.
+func (h *ReputationHandler) GetReputation(c *gin.Context) {
+	address := c.Param("address")
+
+	rep, err := h.reputationService.GetReputation(address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute reputation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rep)
+}
+
+// RecomputeReputation forces a recalculation of an address's reputation
+// score, e.g. after a new report is verified.
+func (h *ReputationHandler) RecomputeReputation(c *gin.Context) {
+	var req struct {
+		Address string `json:"address" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	rep, err := h.reputationService.Recompute(req.Address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute reputation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rep)
+}
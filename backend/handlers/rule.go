@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"Wallet/backend/models"
+	"Wallet/backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RuleHandler manages a wallet's Rule rows, which services.RuleEngine
+// evaluates against every TransactionAnalyzed event.
+type RuleHandler struct {
+	db *gorm.DB
+}
+
+// NewRuleHandler creates a new rule handler.
+func NewRuleHandler(db *gorm.DB) *RuleHandler {
+	return &RuleHandler{db: db}
+}
+
+// ruleRequest is the request body shape for CreateRule/UpdateRule.
+type ruleRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	Expression string   `json:"expression" binding:"required"`
+	Actions    []string `json:"actions" binding:"required"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// CreateRule registers a new rule for the authenticated wallet. Expression
+// is validated against services.SampleRuleContext before it's stored, so a
+// typo'd variable name or malformed syntax is caught at creation time
+// rather than silently never matching at evaluation time.
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req ruleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule format"})
+		return
+	}
+
+	actionsJSON, err := validateRule(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := models.Rule{
+		WalletAddress: address.(string),
+		Name:          req.Name,
+		Expression:    req.Expression,
+		Actions:       actionsJSON,
+		Enabled:       req.Enabled == nil || *req.Enabled,
+	}
+	if err := h.db.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRules lists the authenticated wallet's rules.
+func (h *RuleHandler) GetRules(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var rules []models.Rule
+	if err := h.db.Where("wallet_address = ?", address.(string)).Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// UpdateRule replaces one of the authenticated wallet's rules. Edits take
+// effect on the very next TransactionAnalyzed event, since RuleEngine reads
+// rules fresh from the database rather than caching them.
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var rule models.Rule
+	if err := h.db.Where("id = ? AND wallet_address = ?", c.Param("id"), address.(string)).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+
+	var req ruleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule format"})
+		return
+	}
+
+	actionsJSON, err := validateRule(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule.Name = req.Name
+	rule.Expression = req.Expression
+	rule.Actions = actionsJSON
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := h.db.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule removes one of the authenticated wallet's rules.
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND wallet_address = ?", c.Param("id"), address.(string)).Delete(&models.Rule{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rule"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Rule deleted"})
+}
+
+// validateRule checks req.Expression parses against services.SampleRuleContext
+// and req.Actions are all recognized, returning the JSON-encoded Actions
+// ready to store.
+func validateRule(req ruleRequest) (string, error) {
+	if _, err := services.EvaluateRuleExpression(req.Expression, services.SampleRuleContext()); err != nil {
+		return "", fmt.Errorf("invalid rule expression: %w", err)
+	}
+
+	for _, action := range req.Actions {
+		if action != "auto_block" && !strings.HasPrefix(action, "notify:") {
+			return "", fmt.Errorf("unrecognized action: %s", action)
+		}
+	}
+
+	actionsJSON, err := json.Marshal(req.Actions)
+	if err != nil {
+		return "", fmt.Errorf("invalid actions")
+	}
+	return string(actionsJSON), nil
+}
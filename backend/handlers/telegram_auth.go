@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"Wallet/backend/config"
+	"Wallet/backend/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// telegramSessionTTL is how long the JWT issued by VerifyTelegramLogin stays valid.
+const telegramSessionTTL = 24 * time.Hour
+
+// TelegramAuthHandler verifies Telegram Login Widget / Mini App payloads and
+// issues session JWTs bound to the authenticated Telegram user ID.
+type TelegramAuthHandler struct {
+	telegramService *services.TelegramService
+	cfg             *config.Config
+}
+
+// NewTelegramAuthHandler creates a new Telegram auth handler.
+func NewTelegramAuthHandler(telegramService *services.TelegramService, cfg *config.Config) *TelegramAuthHandler {
+	return &TelegramAuthHandler{
+		telegramService: telegramService,
+		cfg:             cfg,
+	}
+}
+
+// telegramSessionClaims is the JWT payload issued by VerifyTelegramLogin.
+type telegramSessionClaims struct {
+	TelegramUserID int64 `json:"telegramUserId"`
+	jwt.RegisteredClaims
+}
+
+// VerifyTelegramLogin verifies a Telegram Login Widget payload (JSON body
+// {"widget": {id/first_name/.../auth_date/hash}}) or a Mini App initData string
+// (JSON body {"initData": "..."}), and on success returns a session JWT
+// bound to the recovered Telegram user ID.
+func (h *TelegramAuthHandler) VerifyTelegramLogin(c *gin.Context) {
+	var req struct {
+		InitData string            `json:"initData"`
+		Widget   map[string]string `json:"widget"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	ttl := time.Duration(h.cfg.TelegramAuthTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = services.DefaultTelegramAuthTTL
+	}
+
+	var telegramUserID int64
+	var err error
+	switch {
+	case req.InitData != "":
+		telegramUserID, err = services.VerifyTelegramMiniApp(h.telegramService.Token, req.InitData, ttl)
+	case len(req.Widget) > 0:
+		hash := req.Widget["hash"]
+		fields := make(map[string]string, len(req.Widget))
+		for k, v := range req.Widget {
+			if k == "hash" {
+				continue
+			}
+			fields[k] = v
+		}
+		telegramUserID, err = services.VerifyTelegramLoginWidget(h.telegramService.Token, fields, hash, ttl)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request must include either initData or widget"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Telegram verification failed: " + err.Error()})
+		return
+	}
+
+	token, err := h.issueSessionToken(telegramUserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue session token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":          token,
+		"telegramUserId": telegramUserID,
+	})
+}
+
+// issueSessionToken signs a JWT bound to telegramUserID using the same
+// JWTSecret the rest of the backend's JWT-authenticated routes trust.
+func (h *TelegramAuthHandler) issueSessionToken(telegramUserID int64) (string, error) {
+	claims := telegramSessionClaims{
+		TelegramUserID: telegramUserID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(telegramSessionTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(h.cfg.JWTSecret))
+}
+
+// LinkTelegramSession binds the wallet address authenticated by
+// middleware.Web3AuthMiddleware to the Telegram user ID carried by a
+// session JWT from VerifyTelegramLogin, replacing the unauthenticated
+// "/link WALLET_ADDRESS" chat command with a cryptographically verified
+// link in both directions.
+func (h *TelegramAuthHandler) LinkTelegramSession(c *gin.Context) {
+	var req struct {
+		SessionToken string `json:"sessionToken" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	walletAddress, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var claims telegramSessionClaims
+	_, err := jwt.ParseWithClaims(req.SessionToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(h.cfg.JWTSecret), nil
+	})
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired Telegram session token"})
+		return
+	}
+
+	if err := h.telegramService.LinkWalletToTelegramUser(walletAddress.(string), claims.TelegramUserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link Telegram account: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        "Telegram account successfully linked to wallet",
+		"telegramUserId": claims.TelegramUserID,
+	})
+}
+
+// RotateWebhookSecret re-registers the Telegram webhook under a freshly
+// generated secret_token, invalidating any previously issued one. Lets an
+// operator recover from a leaked secret without redeploying.
+func (h *TelegramAuthHandler) RotateWebhookSecret(c *gin.Context) {
+	if err := h.telegramService.RotateWebhookSecret(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate webhook secret: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook secret rotated"})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"Wallet/backend/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// wsUpgrader upgrades an authenticated HTTP request to a WebSocket
+// connection. CheckOrigin accepts every origin, unlike the origin allowlist
+// in routes.SetupMainRouter's CORS config; the gate is
+// middleware.Web3AuthMiddleware on the same route, which runs before the
+// upgrade happens.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// WebSocketHandler exposes a services.WebsocketHub over GET /api/ws.
+type WebSocketHandler struct {
+	hub *services.WebsocketHub
+}
+
+// NewWebSocketHandler creates a WebSocket handler backed by hub.
+func NewWebSocketHandler(hub *services.WebsocketHub) *WebSocketHandler {
+	return &WebSocketHandler{hub: hub}
+}
+
+// HandleConnection upgrades the request and registers the connection with
+// the hub under the wallet address middleware.Web3AuthMiddleware
+// authenticated. It blocks until the client disconnects.
+func (h *WebSocketHandler) HandleConnection(c *gin.Context) {
+	address, exists := c.Get("address")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	h.hub.Register(conn, address.(string))
+}
@@ -0,0 +1,75 @@
+// Package logging provides the structured logger shared across services,
+// replacing ad-hoc fmt.Errorf/log.Printf calls with JSON events that carry a
+// per-request correlation ID. Default() returns the process-wide logger;
+// WithFields attaches a correlation ID and any other fields to a
+// context.Context so a later FromContext(ctx) call logs with them already
+// bound - see middleware.CorrelationIDMiddleware for where the correlation
+// ID itself comes from.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+)
+
+// level backs the default logger's slog.Handler so SetLevel can change it
+// at runtime (wired to config.Config.LogLevel and config.Watcher reloads)
+// without rebuilding the logger.
+var level = new(slog.LevelVar)
+
+var defaultLogger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+
+// Default returns the process-wide JSON logger.
+func Default() *slog.Logger {
+	return defaultLogger
+}
+
+// SetLevel parses levelName ("debug", "info", "warn", "error") and applies
+// it to Default() and every logger derived from it. Unrecognized values
+// fall back to info.
+func SetLevel(levelName string) {
+	switch levelName {
+	case "debug":
+		level.Set(slog.LevelDebug)
+	case "warn", "warning":
+		level.Set(slog.LevelWarn)
+	case "error":
+		level.Set(slog.LevelError)
+	default:
+		level.Set(slog.LevelInfo)
+	}
+}
+
+type contextKey struct{}
+
+// NewCorrelationID generates a random identifier for a single request, for
+// middleware.CorrelationIDMiddleware to attach via WithFields and for
+// propagating to downstream calls (e.g. the Civic gateway) that accept one.
+func NewCorrelationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// WithFields returns a context carrying a logger derived from
+// FromContext(ctx) with args attached (same key/value pairing slog.Logger.With
+// takes), so subsequent FromContext(ctx).Info(...) calls include them
+// automatically.
+func WithFields(ctx context.Context, args ...any) context.Context {
+	logger := FromContext(ctx).With(args...)
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger attached by WithFields, or Default() if
+// ctx carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return Default()
+}
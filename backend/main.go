@@ -2,24 +2,70 @@ package main
 
 import (
 	"Wallet/backend/config"
+	"Wallet/backend/handlers"
+	"Wallet/backend/logging"
+	"Wallet/backend/pkg/observability"
 	"Wallet/backend/routes"
 	"Wallet/backend/services"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// daoTallyInterval controls how often expired DAO proposals are tallied.
+const daoTallyInterval = 1 * time.Minute
+
+// serverVersion identifies this binary for the queued-upgrade startup check.
+// Bump it alongside the version string printed in the startup banner below.
+const serverVersion = "1.0.0"
+
+// startDAOTallyTicker periodically finalizes DAO proposals whose voting
+// period has elapsed, flipping their Status to passed/rejected/failed_quorum
+// without requiring a client to hit GET /proposals/:id/tally first.
+func startDAOTallyTicker(daoHandler *handlers.DAOHandler) {
+	ticker := time.NewTicker(daoTallyInterval)
+	go func() {
+		for range ticker.C {
+			if err := daoHandler.TallyExpiredProposals(); err != nil {
+				log.Printf("DAO tally ticker: failed to tally expired proposals: %v", err)
+			}
+		}
+	}()
+}
+
 func main() {
 	log.Println("Starting Wallet Backend Service...")
 
-	// Load configuration
+	// Load configuration. cfgUpdates delivers every subsequently hot
+	// reloaded Config (see config.Watcher) until the process exits.
 	log.Println("Loading configuration...")
-	cfg, err := config.LoadConfig()
+	cfg, cfgUpdates, err := config.LoadConfigWithWatcher(context.Background())
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	logging.SetLevel(cfg.LogLevel)
+
+	shutdownTracing, err := observability.InitTracing(context.Background(), cfg.OTLPEndpoint, cfg.TracingSampleRate)
+	if err != nil {
+		log.Printf("Warning: tracing disabled: %v", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+	defer shutdownTracing(context.Background())
+
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		log.Printf("Metrics listener starting on port %s...", cfg.MetricsPort)
+		if err := http.ListenAndServe(":"+cfg.MetricsPort, mux); err != nil {
+			log.Printf("Metrics listener stopped: %v", err)
+		}
+	}()
 
 	// Initialize database
 	log.Println("Connecting to database...")
@@ -27,14 +73,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
+	dbPool := config.NewDBPool(db, cfg)
 	// Run database migrations and setup
 	log.Println("Setting up database schema...")
 	if err := config.InitializeDatabase(db); err != nil {
 		log.Fatalf("Failed to initialize database schema: %v", err)
 	}
+
+	// Refuse to boot if a DAO "upgrade" proposal has queued a target
+	// version other than this binary's. This only compares the version string
+	// recorded in the proposal payload; it doesn't wait for ActivationBlock,
+	// since doing so would require standing up a blockchain client before
+	// routes.SetupMainRouter constructs one.
+	if err := config.RefuseIfTooOld(db, serverVersion); err != nil {
+		log.Fatalf("Refusing to start: %v", err)
+	}
+
 	// Initialize Telegram service
 	log.Println("Initializing Telegram bot service...")
 	telegramService := services.NewTelegramService(cfg.TelegramToken, db)
+	telegramService.SetWebhookIPAllowlist(cfg.TelegramWebhookIPAllowlist)
 
 	// Set Telegram webhook URL if in production
 	if cfg.Environment == "production" {
@@ -46,7 +104,49 @@ func main() {
 		log.Println("Telegram webhooks not set in development mode. Use a tunnel like ngrok for local testing.")
 	}	// Setup router with services
 	log.Println("Setting up API routes...")
-	r := routes.SetupMainRouter(db, telegramService)
+	r, daoHandler, civicService, proposalExecutor := routes.SetupMainRouter(db, telegramService, cfg)
+
+	// Start the background DAO proposal tallying loop
+	startDAOTallyTicker(daoHandler)
+
+	// Start the background DAO proposal execution engine, if configured
+	// (see routes.SetupMainRouter for when it's nil).
+	if proposalExecutor != nil {
+		go proposalExecutor.Start(context.Background())
+	}
+
+	// Apply each hot reloaded config to the components that know how to pick
+	// it up without a restart. Handlers that captured db/cfg by value at
+	// SetupMainRouter time (most of them, today) won't see a DatabaseURL
+	// change until the process restarts - see DBPool's doc comment.
+	go func() {
+		for newCfg := range cfgUpdates {
+			if err := dbPool.Reload(newCfg); err != nil {
+				log.Printf("Warning: failed to reload database pool: %v", err)
+			}
+			civicService.Reload(newCfg)
+			logging.SetLevel(newCfg.LogLevel)
+			log.Println("Applied reloaded configuration")
+		}
+	}()
+
+	// Optionally start a dedicated mTLS listener for the Civic auth
+	// service's certificate-based auth channel (see
+	// services.CivicAuthService.VerifyPeerCertificate). Off by default:
+	// this service normally sits behind a reverse proxy that terminates TLS.
+	if cfg.MTLSEnabled {
+		if tlsConfig, err := civicService.ServerTLSConfig(cfg.BaseURL); err != nil {
+			log.Printf("Warning: mTLS listener disabled: %v", err)
+		} else {
+			mtlsServer := &http.Server{Addr: ":" + cfg.MTLSPort, Handler: r, TLSConfig: tlsConfig}
+			go func() {
+				log.Printf("mTLS listener starting on port %s...", cfg.MTLSPort)
+				if err := mtlsServer.ListenAndServeTLS("", ""); err != nil {
+					log.Printf("mTLS listener stopped: %v", err)
+				}
+			}()
+		}
+	}
 
 	// Get port from environment or use default
 	port := os.Getenv("PORT")
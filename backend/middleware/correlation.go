@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"Wallet/backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CorrelationIDHeader is echoed on every response and, if a client sets it
+// on the request, reused instead of generating a new ID - useful for a
+// caller that wants to correlate its own logs with this service's.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// CorrelationIDMiddleware attaches a correlation ID to the request's
+// context (via logging.WithFields, so every logging.FromContext(ctx) call
+// made while handling it includes "correlation_id" automatically), exposes
+// it as gin context key "correlation_id" for handlers that want it
+// directly, and echoes it back in the response header.
+func CorrelationIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if correlationID == "" {
+			correlationID = logging.NewCorrelationID()
+		}
+
+		ctx := logging.WithFields(c.Request.Context(), "correlation_id", correlationID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set("correlation_id", correlationID)
+		c.Header(CorrelationIDHeader, correlationID)
+
+		c.Next()
+	}
+}
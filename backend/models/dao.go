@@ -6,24 +6,99 @@ import (
 
 // DAOProposal represents a governance proposal in the DAO
 type DAOProposal struct {
-	ID             uint      `json:"id" gorm:"primaryKey"`
-	Title          string    `json:"title"`
-	Description    string    `json:"description"`
-	CreatorAddress string    `json:"creatorAddress" gorm:"index;column:proposer_address"`
-	CreatedAt      time.Time `json:"createdAt"`
-	EndTime        time.Time `json:"endTime"`
-	Status         string    `json:"status"` // "active", "passed", "rejected", "executed"
-	VotesFor       int       `json:"votesFor"`
-	VotesAgainst   int       `json:"votesAgainst"`
-	ExecutionData  string    `json:"executionData"` // contract call data if proposal passes
+	ID             uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	Title          string    `json:"title" bson:"title"`
+	Description    string    `json:"description" bson:"description"`
+	CreatorAddress string    `json:"creatorAddress" gorm:"index;column:proposer_address" bson:"creator_address"`
+	CreatedAt      time.Time `json:"createdAt" bson:"created_at"`
+	EndTime        time.Time `json:"endTime" bson:"end_time"`
+	// Status is "active", "passed", "rejected", "failed_quorum", "executing",
+	// or "executed". "executing" is dao.ProposalExecutor's idempotency claim
+	// on a proposal mid-submission - see its doc comment - and is otherwise
+	// transient: it resolves to "executed" on success or back to "passed" on
+	// a failed attempt.
+	Status       string `json:"status" bson:"status"`
+	VotesFor     int    `json:"votesFor" bson:"votes_for"`
+	VotesAgainst int    `json:"votesAgainst" bson:"votes_against"`
+	// ExecutionData is ABI-encoded calldata for a direct governance contract
+	// call, submitted on-chain by dao.ProposalExecutor once Status reaches
+	// "passed" - distinct from Payload/ProposalType below, which the
+	// DAOHandler executes in-process (config changes, treasury spends,
+	// upgrade queuing). Empty means this proposal has nothing to execute
+	// on-chain.
+	ExecutionData string `json:"executionData" bson:"execution_data"`
+	// ChainID is the EIP-155 chain ExecutionData is submitted to, resolved
+	// against dao.ExecutorConfig.Chains. Defaults to the server's own
+	// config.Config.ChainID when a proposal doesn't specify one.
+	ChainID int64 `json:"chainId" bson:"chain_id"`
+	// ExecutionTxHash is the transaction hash dao.ProposalExecutor recorded
+	// after successfully broadcasting ExecutionData. Empty until executed.
+	ExecutionTxHash string `json:"executionTxHash" bson:"execution_tx_hash"`
+
+	// SnapshotBlock pins the block height at which token balances are read to
+	// compute VotePower, so a voter can't buy in after a proposal is created.
+	SnapshotBlock uint64 `json:"snapshotBlock" bson:"snapshot_block"`
+	// Quorum is the minimum total power (for+against+abstain) required for the
+	// proposal to be anything other than "failed_quorum" once EndTime elapses.
+	Quorum float64 `json:"quorum" bson:"quorum"`
+	// Threshold is the fraction of (for / (for+against)) power required to pass.
+	Threshold    float64 `json:"threshold" bson:"threshold"`
+	VotingPeriod int64   `json:"votingPeriod" bson:"voting_period"` // seconds, used to derive EndTime from CreatedAt
+
+	ForPower     float64 `json:"forPower" bson:"for_power"`
+	AgainstPower float64 `json:"againstPower" bson:"against_power"`
+	AbstainPower float64 `json:"abstainPower" bson:"abstain_power"`
+
+	// ProposalType selects how ExecuteProposal interprets Payload once the
+	// proposal passes: "text" (no execution), "param_change",
+	// "treasury_spend", or "upgrade".
+	ProposalType string `json:"proposalType" gorm:"default:text" bson:"proposal_type"`
+	// Payload is the type-specific execution data, stored as JSON text and
+	// decoded against ParamChangePayload/TreasurySpendPayload/UpgradePayload
+	// depending on ProposalType. CreateProposal rejects a payload that
+	// doesn't parse against its declared ProposalType.
+	Payload string `json:"payload" gorm:"type:jsonb" bson:"payload"`
+}
+
+// ParamChangePayload is the Payload shape for a "param_change" proposal: it
+// applies a single key/value update to the Config table.
+type ParamChangePayload struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
-// DAOVote represents a vote cast by a user for a DAO proposal
+// TreasurySpendPayload is the Payload shape for a "treasury_spend" proposal:
+// it submits a signed multisig transaction to the treasury contract.
+type TreasurySpendPayload struct {
+	ToAddress    string  `json:"toAddress"`
+	Amount       float64 `json:"amount"`
+	TokenAddress string  `json:"tokenAddress"` // empty means native token
+}
+
+// UpgradePayload is the Payload shape for an "upgrade" proposal: it records
+// the binary version the running server must report and an activation
+// block. The startup check (config.RefuseIfTooOld) compares TargetVersion
+// for exact equality and does not read ActivationBlock.
+type UpgradePayload struct {
+	TargetVersion   string `json:"targetVersion"`
+	ActivationBlock uint64 `json:"activationBlock"`
+}
+
+// DAOVote represents a vote cast by a user for a DAO proposal. The
+// (ProposalID, VoterAddress) uniqueIndex is what prevents double voting:
+// CastVote does no existing-vote lookup and treats a failed insert as a
+// repeat vote, since two concurrent requests could both pass such a read
+// before either has inserted.
 type DAOVote struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	ProposalID   uint      `json:"proposalId" gorm:"index"`
-	VoterAddress string    `json:"voterAddress" gorm:"index"`
-	VoteType     string    `json:"voteType"` // "for" or "against"
-	VotePower    float64   `json:"votePower"`
-	VotedAt      time.Time `json:"votedAt"`
+	ID           uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	ProposalID   uint      `json:"proposalId" gorm:"uniqueIndex:idx_proposal_voter" bson:"proposal_id"`
+	VoterAddress string    `json:"voterAddress" gorm:"uniqueIndex:idx_proposal_voter" bson:"voter_address"`
+	VoteType     string    `json:"voteType" bson:"vote_type"` // "for", "against" or "abstain"
+	VotePower    float64   `json:"votePower" bson:"vote_power"`
+	VotedAt      time.Time `json:"votedAt" bson:"voted_at"`
+
+	// Signature is the EIP-712 signature over the vote payload (proposal id,
+	// vote type, voter address) that RecoverVoteSigner recovers the signer
+	// from, so the server never trusts a client-supplied VoterAddress.
+	Signature string `json:"signature" gorm:"column:signature" bson:"signature"`
 }
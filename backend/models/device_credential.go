@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DeviceCredential records an X.509 client certificate issued by pkg/pki so
+// services.CivicAuthService can authenticate a device by the certificate it
+// presents over mTLS instead of polling a Civic gatepass token. A presented
+// certificate is trusted only while its Fingerprint has a row here with no
+// RevokedAt and an ExpiresAt in the future - see
+// CivicAuthService.VerifyPeerCertificate.
+type DeviceCredential struct {
+	ID           uint       `json:"id" gorm:"primaryKey" bson:"id"`
+	UserAddress  string     `json:"userAddress" gorm:"index" bson:"user_address"`
+	DeviceInfo   string     `json:"deviceInfo" bson:"device_info"`
+	Fingerprint  string     `json:"fingerprint" gorm:"uniqueIndex" bson:"fingerprint"`
+	SerialNumber string     `json:"serialNumber" bson:"serial_number"`
+	IssuedAt     time.Time  `json:"issuedAt" bson:"issued_at"`
+	ExpiresAt    time.Time  `json:"expiresAt" bson:"expires_at"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty" bson:"revoked_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// Nonce records a (address, nonce) pair that has been consumed by a signed
+// API request, so RequireSignedRequest can reject replays of the same
+// signed body. The unique index is the actual replay guard; IssuedAt is
+// kept only so stale rows can be pruned later.
+type Nonce struct {
+	ID       uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	Address  string    `json:"address" gorm:"uniqueIndex:idx_address_nonce" bson:"address"`
+	Nonce    string    `json:"nonce" gorm:"uniqueIndex:idx_address_nonce" bson:"nonce"`
+	IssuedAt time.Time `json:"issuedAt" bson:"issued_at"`
+}
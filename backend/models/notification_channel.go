@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationChannel is one destination a wallet owner has registered to
+// receive security alerts on, beyond the built-in Telegram bot link.
+type NotificationChannel struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	WalletAddress string    `json:"walletAddress" gorm:"index"`
+	// ChannelType selects the services.Notifier that handles this channel:
+	// "telegram", "slack", "discord", "webhook", "email", or "n8n".
+	ChannelType string `json:"channelType" gorm:"index"`
+	// Target is the channel-specific destination: a Telegram chat ID, a
+	// Slack/Discord/webhook/n8n URL, or an email address.
+	Target string `json:"target"`
+	// Secret holds channel-specific credentials (e.g. an n8n basic-auth
+	// pair or custom header, JSON-encoded) and is never returned in API
+	// responses that echo back a channel.
+	Secret string `json:"-"`
+	// MinSeverity is the lowest event severity ("low", "medium", "high",
+	// "critical") this channel wants to hear about.
+	MinSeverity string    `json:"minSeverity" gorm:"default:low"`
+	Enabled     bool      `json:"enabled" gorm:"default:true"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NotificationDeadLetter records a notification that exhausted its retry
+// budget on a given channel, so operators can inspect and replay failed
+// deliveries instead of silently losing them.
+type NotificationDeadLetter struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ChannelID   uint      `json:"channelId" gorm:"index"`
+	ChannelType string    `json:"channelType"`
+	EventType   string    `json:"eventType"`
+	Payload     string    `json:"payload" gorm:"type:jsonb"`
+	Error       string    `json:"error"`
+	Attempts    int       `json:"attempts"`
+	FailedAt    time.Time `json:"failedAt"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// PendingAction maps a short callback token embedded in an inline-keyboard
+// button's callback_data to the action it should perform once pressed.
+// Telegram limits callback_data to 64 bytes, so the button carries only
+// this token rather than the transaction id/wallet/action directly.
+type PendingAction struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+	// Token is the opaque value sent as callback_data.
+	Token  string `json:"token" gorm:"uniqueIndex"`
+	ChatID string `json:"chatId" gorm:"index"`
+	// Action is one of "allow_once", "block_and_report", "always_allow_sender".
+	Action        string    `json:"action"`
+	TransactionID uint      `json:"transactionId"`
+	WalletAddress string    `json:"walletAddress"`
+	CounterParty  string    `json:"counterParty"` // the address the action applies to (tx.ToAddress)
+	ExpiresAt     time.Time `json:"expiresAt"`
+	Used          bool      `json:"used" gorm:"default:false"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// AddressAllowlistEntry records that a wallet has chosen, via the "Always
+// allow sender" inline button, to stop receiving suspicious-transaction
+// alerts for a given counterparty address.
+type AddressAllowlistEntry struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	WalletAddress  string    `json:"walletAddress" gorm:"uniqueIndex:idx_allowlist_pair"`
+	AllowedAddress string    `json:"allowedAddress" gorm:"uniqueIndex:idx_allowlist_pair"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// ChatConversationState tracks an in-progress multi-step conversation (the
+// /block and /report flows) for a single Telegram chat, since updates
+// arrive one message at a time with no built-in session concept.
+type ChatConversationState struct {
+	ID     uint   `json:"id" gorm:"primaryKey"`
+	ChatID string `json:"chatId" gorm:"uniqueIndex"`
+	// Flow is "block" or "report"; Step is a flow-specific step name.
+	Flow string `json:"flow"`
+	Step string `json:"step"`
+	// Context holds flow-specific partial input gathered so far, JSON-encoded.
+	Context   string    `json:"context" gorm:"type:jsonb"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
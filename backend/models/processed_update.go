@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// ProcessedTelegramUpdate records a Telegram update_id the webhook handler
+// has already acted on, so Telegram's at-least-once delivery retries don't
+// get processed twice. Rows older than the service's processedUpdateTTL are
+// pruned opportunistically rather than by a separate cleanup job.
+type ProcessedTelegramUpdate struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UpdateID  int64     `json:"updateId" gorm:"uniqueIndex"`
+	CreatedAt time.Time `json:"createdAt"`
+}
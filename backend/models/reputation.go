@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AddressReputation is the computed, decayed trust score for an address,
+// derived from confirmed Report entries (and, as those subsystems land,
+// DAO-proposal outcomes and observed transaction anomalies). It replaces the
+// two-entry hardcoded blacklist AIService.IsAddressBlacklisted used to check.
+type AddressReputation struct {
+	ID            uint      `json:"id" gorm:"primaryKey" bson:"id"`
+	Address       string    `json:"address" gorm:"uniqueIndex;size:42" bson:"address"`
+	Score         float64   `json:"score" bson:"score"`           // 0 (trusted) to 1 (confirmed malicious)
+	Confidence    float64   `json:"confidence" bson:"confidence"` // 0 to 1, grows with EvidenceCount
+	LastComputed  time.Time `json:"lastComputed" bson:"last_computed"`
+	EvidenceCount int       `json:"evidenceCount" bson:"evidence_count"`
+}
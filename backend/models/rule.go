@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Rule is a per-wallet, hot-reloaded condition evaluated by
+// services.RuleEngine against every TransactionAnalyzed event on
+// services.EventBus. Expression is a small boolean DSL (see
+// services.EvaluateRuleExpression) over signals like risk, value, velocity,
+// watchlist membership, and report count, e.g.:
+//
+//	risk > 0.5 AND to_address in watchlist
+//
+// Actions lists what to do when Expression matches, e.g.
+// ["notify:telegram", "notify:email", "auto_block"].
+type Rule struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	WalletAddress string    `json:"walletAddress" gorm:"index"`
+	Name          string    `json:"name"`
+	Expression    string    `json:"expression"`
+	Actions       string    `json:"actions" gorm:"type:jsonb"`
+	Enabled       bool      `json:"enabled" gorm:"default:true"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
@@ -11,11 +11,17 @@ type TelegramMapping struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
 	WalletAddress string         `gorm:"index;size:42" json:"wallet_address"`
 	ChatID        string         `gorm:"index" json:"chat_id"`
-	UserName      string         `json:"user_name"`
-	FirstName     string         `json:"first_name"`
-	LastName      string         `json:"last_name"`
-	IsActive      bool           `gorm:"default:true" json:"is_active"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	// TelegramUserID is the numeric Telegram user ID recovered from a
+	// verified Login Widget or Mini App payload. Unlike ChatID (which, for
+	// bot DMs, happens to equal the user ID but isn't guaranteed to in
+	// general), this is always the user's own ID, so it's what
+	// link-session binds a wallet to.
+	TelegramUserID int64          `gorm:"index" json:"telegram_user_id"`
+	UserName       string         `json:"user_name"`
+	FirstName      string         `json:"first_name"`
+	LastName       string         `json:"last_name"`
+	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
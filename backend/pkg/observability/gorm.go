@@ -0,0 +1,89 @@
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey stores the in-flight span on the Statement's Context so the
+// "after" callback can end the span its matching "before" callback started.
+// GORM gives each call its own *gorm.Statement, so keying off its Context is
+// safe across concurrent queries.
+type gormSpanKey struct{}
+
+// InstrumentGORM registers before/after callbacks on db's Create, Query,
+// Update, Delete, Row, and Raw callback chains that wrap each query in an
+// OTel span, so a trace started at API ingress continues through to the
+// database. It doesn't add Prometheus metrics - GORM's callback API doesn't
+// expose a clean per-query outcome label until "after" runs, and this
+// chunk's Metrics don't include a generic DB-query instrument.
+func InstrumentGORM(db *gorm.DB) error {
+	// db.Callback().Create() (and Query/Update/Delete/Row/Raw) return GORM's
+	// unexported *processor type, so each chain has to be registered inline,
+	// right off the Callback() call - it can't be captured in a variable,
+	// struct field, or a local interface type naming its return value.
+	if err := db.Callback().Create().Before("*").Register("observability:before_create", beforeGORMHook("create")); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("*").Register("observability:after_create", endGORMSpan); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("*").Register("observability:before_query", beforeGORMHook("query")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("*").Register("observability:after_query", endGORMSpan); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("*").Register("observability:before_update", beforeGORMHook("update")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("*").Register("observability:after_update", endGORMSpan); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("*").Register("observability:before_delete", beforeGORMHook("delete")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("*").Register("observability:after_delete", endGORMSpan); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Register("observability:before_row", beforeGORMHook("row")); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("*").Register("observability:after_row", endGORMSpan); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().Before("*").Register("observability:before_raw", beforeGORMHook("raw")); err != nil {
+		return err
+	}
+	if err := db.Callback().Raw().After("*").Register("observability:after_raw", endGORMSpan); err != nil {
+		return err
+	}
+	return nil
+}
+
+// beforeGORMHook returns a "before" callback that starts a span named
+// "gorm.<name>" and stashes it on the statement's Context for endGORMSpan to
+// close.
+func beforeGORMHook(name string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx, span := Tracer().Start(tx.Statement.Context, "gorm."+name)
+		span.SetAttributes(attribute.String("db.table", tx.Statement.Table))
+		tx.Statement.Context = context.WithValue(ctx, gormSpanKey{}, span)
+	}
+}
+
+func endGORMSpan(tx *gorm.DB) {
+	span, ok := tx.Statement.Context.Value(gormSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if tx.Error != nil {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+	span.End()
+}
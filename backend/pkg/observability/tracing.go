@@ -0,0 +1,55 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans this service creates, independent of the
+// OTLP endpoint they're exported to.
+const tracerName = "Wallet/backend"
+
+// InitTracing configures the global OTel tracer provider to export spans via
+// OTLP/gRPC to otlpEndpoint, sampling a sampleRate fraction of traces (1.0
+// traces everything, 0 disables sampling entirely). It returns a shutdown
+// func the caller should defer/call on process exit to flush pending spans.
+// If otlpEndpoint is empty, tracing is left as the OTel no-op default and
+// shutdown is a no-op - this is the common case for a local/dev deployment
+// that hasn't stood up a collector.
+func InitTracing(ctx context.Context, otlpEndpoint string, sampleRate float64) (func(context.Context) error, error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(otlpEndpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName("wallet-backend")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(sampleRate)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the named tracer every instrumented call site (the Civic
+// gateway call, the GORM query callbacks) starts its spans from.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
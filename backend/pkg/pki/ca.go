@@ -0,0 +1,186 @@
+// Package pki implements a small self-managed X.509 certificate authority
+// used to issue per-device mTLS client certificates for
+// services.CivicAuthService's certificate-based authentication channel. It
+// favors the standard library's crypto/x509 over a cfssl dependency (this
+// repo has no dependency manifest to vendor one against), but mirrors
+// cfssl's ca.json/ca-config.json shape so the config is familiar to anyone
+// who has operated a cfssl-based CA.
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// CAConfig mirrors cfssl's ca.json: the root CA's subject and key validity.
+type CAConfig struct {
+	CommonName       string
+	Organization     string
+	Country          string
+	ValidityDuration time.Duration // defaults to 10 years when zero
+}
+
+// SigningProfile mirrors one entry under cfssl's ca-config.json
+// "signing.profiles": how long issued certs are valid and which extended
+// key usages they carry.
+type SigningProfile struct {
+	Expiry time.Duration
+	Usages []x509.ExtKeyUsage
+}
+
+// CAConfigProfiles mirrors cfssl's ca-config.json: the named signing
+// profiles for the two certificate classes this CA issues.
+type CAConfigProfiles struct {
+	ServerAuth SigningProfile
+	ClientAuth SigningProfile
+}
+
+// DefaultProfiles is the profile set NewCA falls back to when the zero
+// value is passed.
+var DefaultProfiles = CAConfigProfiles{
+	ServerAuth: SigningProfile{Expiry: 365 * 24 * time.Hour, Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}},
+	ClientAuth: SigningProfile{Expiry: 90 * 24 * time.Hour, Usages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}},
+}
+
+// CA is a self-managed certificate authority: an in-memory root key/cert
+// pair plus the signing profiles it issues leaf certificates under. The
+// root key is generated fresh per-process and is never persisted, so
+// certificate validation should not rely on full chain verification
+// surviving a restart - see CivicAuthService.VerifyPeerCertificate, which
+// checks a presented certificate's fingerprint against the DeviceCredential
+// table instead.
+type CA struct {
+	profiles CAConfigProfiles
+
+	cert    *x509.Certificate
+	certDER []byte
+	key     *ecdsa.PrivateKey
+}
+
+// NewCA generates a fresh self-signed root CA from config. profiles
+// defaults to DefaultProfiles when passed as the zero value.
+func NewCA(config CAConfig, profiles CAConfigProfiles) (*CA, error) {
+	if profiles.ServerAuth.Expiry == 0 && profiles.ClientAuth.Expiry == 0 {
+		profiles = DefaultProfiles
+	}
+	if config.ValidityDuration == 0 {
+		config.ValidityDuration = 10 * 365 * 24 * time.Hour
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   config.CommonName,
+			Organization: []string{config.Organization},
+			Country:      []string{config.Country},
+		},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(config.ValidityDuration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to self-sign CA certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse freshly created CA certificate: %w", err)
+	}
+
+	return &CA{profiles: profiles, cert: cert, certDER: certDER, key: key}, nil
+}
+
+// CertPEM returns the CA's certificate in PEM form, for distribution to
+// clients that need to build a trust bundle for the server side of mTLS.
+func (ca *CA) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.certDER})
+}
+
+// Certificate returns the parsed CA certificate.
+func (ca *CA) Certificate() *x509.Certificate {
+	return ca.cert
+}
+
+// IssueClientCert issues a client-auth leaf certificate for commonName
+// (conventionally the wallet address), signed by ca, and returns it
+// PEM-encoded alongside its private key, the SHA-256 fingerprint of the
+// DER-encoded certificate, and its serial number.
+func (ca *CA) IssueClientCert(commonName string) (certPEM, keyPEM []byte, fingerprint string, serialNumber *big.Int, err error) {
+	return ca.issueLeaf(commonName, ca.profiles.ClientAuth)
+}
+
+// IssueServerCert issues a server-auth leaf certificate, e.g. for a
+// bouncer's own TLS listener.
+func (ca *CA) IssueServerCert(commonName string) (certPEM, keyPEM []byte, fingerprint string, serialNumber *big.Int, err error) {
+	return ca.issueLeaf(commonName, ca.profiles.ServerAuth)
+}
+
+func (ca *CA) issueLeaf(commonName string, profile SigningProfile) (certPEM, keyPEM []byte, fingerprint string, serialNumber *big.Int, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to generate leaf key: %w", err)
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to generate leaf serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(profile.Expiry),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  profile.Usages,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to sign leaf certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to marshal leaf key: %w", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, Fingerprint(der), serial, nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint (lowercase hex) of a
+// DER-encoded certificate, in the same form IssueClientCert returns, for
+// comparing a presented certificate against a DeviceCredential row.
+func Fingerprint(certDER []byte) string {
+	sum := sha256.Sum256(certDER)
+	return hex.EncodeToString(sum[:])
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
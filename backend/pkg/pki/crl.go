@@ -0,0 +1,50 @@
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// crlValidity bounds how long a generated CRL is valid before clients
+// should fetch a fresh one.
+const crlValidity = 24 * time.Hour
+
+// RevokedCert is one entry in a CRL: the serial number of a revoked leaf
+// certificate and when it was revoked.
+type RevokedCert struct {
+	SerialNumber *big.Int
+	RevokedAt    time.Time
+}
+
+// GenerateCRL produces a DER-encoded Certificate Revocation List, signed by
+// ca, listing revoked.
+func (ca *CA) GenerateCRL(revoked []RevokedCert) ([]byte, error) {
+	entries := make([]pkix.RevokedCertificate, len(revoked))
+	for i, r := range revoked {
+		entries[i] = pkix.RevokedCertificate{
+			SerialNumber:   r.SerialNumber,
+			RevocationTime: r.RevokedAt,
+		}
+	}
+
+	number, err := newSerialNumber()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CRL number: %w", err)
+	}
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              number,
+		ThisUpdate:          time.Now(),
+		NextUpdate:          time.Now().Add(crlValidity),
+		RevokedCertificates: entries,
+	}, ca.cert, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CRL: %w", err)
+	}
+
+	return crlDER, nil
+}
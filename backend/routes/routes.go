@@ -1,12 +1,16 @@
 package routes
 
 import (
+	"Wallet/backend/config"
 	"Wallet/backend/handlers"
 	"Wallet/backend/middleware"
 	"Wallet/backend/services"
+	"Wallet/backend/services/dao"
+	"Wallet/backend/storage"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -14,14 +18,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRouter configures all API routes
-func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gin.Engine {
+// SetupMainRouter configures all API routes. It also returns the DAOHandler
+// so callers (main.go) can drive the periodic proposal-tallying ticker off
+// the same handler instance the HTTP routes use, the CivicAuthService so
+// main.go can optionally terminate a dedicated mTLS listener with its
+// VerifyPeerCertificate hook, and the dao.ProposalExecutor (nil if
+// GOVERNOR_PRIVKEY/DAO_EXECUTOR_CHAINS aren't configured) so main.go can
+// start its polling loop.
+func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService, cfg *config.Config) (*gin.Engine, *handlers.DAOHandler, *services.CivicAuthService, *dao.ProposalExecutor) {
 	r := gin.Default()
+	// Attach a correlation ID to every request before anything else handles
+	// it, so CORS/rate-limit rejections and handler logs alike can be tied
+	// back to the same X-Correlation-ID.
+	r.Use(middleware.CorrelationIDMiddleware())
 	// Configure CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000", "https://*.onrender.com", "https://*.vercel.app"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Wallet-Address", "X-Wallet-Signature", "X-Wallet-Message", "X-User-Address"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Wallet-Address", "X-Wallet-Signature", "X-Wallet-Message", "X-User-Address", middleware.CorrelationIDHeader},
+		ExposeHeaders:    []string{middleware.CorrelationIDHeader},
 		AllowCredentials: true,
 	}))
 	// Health check endpoint for Render and monitoring
@@ -55,24 +70,103 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 	}
 
 	aiService := services.NewAIService(analyticsService)
+	if cfg.RiskModelPath != "" {
+		if onnxScorer, err := services.NewONNXRiskScorer(cfg.RiskModelPath); err != nil {
+			log.Printf("Warning: Failed to load ONNX risk model %s, falling back to rules scorer: %v", cfg.RiskModelPath, err)
+		} else {
+			aiService = services.NewAIServiceWithScorer(analyticsService, onnxScorer)
+		}
+	}
+
+	reputationService := services.NewReputationService(db)
+	aiService.SetReputationService(reputationService)
 
-	// Initialize Civic Auth service
+	// Notification fan-out: one NotificationService shared by every handler
+	// that used to reach into telegramService directly, registered with
+	// every built-in sink. Handlers skip channels whose type has no
+	// registered Notifier, so adding a new sink here is all it takes.
+	notificationService := services.NewNotificationService(db)
+	notificationService.Register(services.NewTelegramNotifier(telegramService))
+	notificationService.Register(services.NewSlackNotifier())
+	notificationService.Register(services.NewDiscordNotifier())
+	notificationService.Register(services.NewWebhookNotifier())
+	notificationService.Register(services.NewN8NNotifier())
+	notificationService.Register(services.NewEmailNotifier(cfg))
+
+	// Initialize Civic Auth service. Sourced from cfg (not os.Getenv
+	// directly) so CivicAuthService.Reload can rebuild this from a hot
+	// reloaded cfg - see config.Watcher.
 	civicConfig := &services.CivicConfig{
-		GatekeeperNetwork: os.Getenv("CIVIC_GATEKEEPER_NETWORK"),
-		ChainId:          11155111, // Sepolia testnet
-		ApiKey:           os.Getenv("CIVIC_API_KEY"),
-		Stage:            os.Getenv("CIVIC_STAGE"), // "prod" or "preprod"
+		GatekeeperNetwork: cfg.CivicGatekeeperNetwork,
+		ChainId:           cfg.ChainID,
+		ApiKey:            cfg.CivicAPIKey,
+		Stage:             cfg.CivicStage, // "prod" or "preprod"
 	}
 	
-	civicService := services.NewCivicAuthService(db, civicConfig)
+	// CivicAuthService has been migrated onto the storage.SessionStore /
+	// storage.VerificationLogStore interfaces (see storage package doc); wrap
+	// the existing *gorm.DB rather than introducing a second connection.
+	civicStorage := storage.NewGormStorage(db)
+	geoIP, err := services.NewGeoIPLookup(cfg.GeoIPDBPath)
+	if err != nil {
+		log.Printf("Warning: GeoIP lookups disabled: %v", err)
+		geoIP, _ = services.NewGeoIPLookup("")
+	}
+	civicService := services.NewCivicAuthService(civicStorage, civicStorage, civicConfig, geoIP)
 	civicHandler := handlers.NewCivicAuthHandler(civicService)
 
+	// Declarative, per-wallet rules (watchlists, velocity/value thresholds,
+	// auto_block, ...) evaluated against every TransactionAnalyzed event,
+	// on top of the baseline AI risk classification in AnalyzeTransaction.
+	eventBus := services.NewEventBus()
+	services.NewRuleEngine(db, eventBus, notificationService)
+
 	// Create handler instances with the database connection and services
 	firewallHandler := handlers.NewFirewallHandler(db, aiService, telegramService)
+	firewallHandler.SetNotificationService(notificationService)
+	firewallHandler.SetEventBus(eventBus)
+
+	// Real-time transaction/alert/report stream for browser and mobile
+	// clients, mirroring the Telegram notification path over the same
+	// EventBus instead of requiring clients to poll.
+	wsHub := services.NewWebsocketHub(eventBus)
+	wsHub.SetAnalyzeFunc(firewallHandler.Analyze)
+	firewallHandler.SetWebsocketHub(wsHub)
+	wsHandler := handlers.NewWebSocketHandler(wsHub)
 	reportHandler := handlers.NewReportHandler(db, blockchainService, telegramService)
-	daoHandler := handlers.NewDAOHandler(db, blockchainService)
+	reportHandler.SetNotificationService(notificationService)
+	reportHandler.SetEventBus(eventBus)
+	notificationHandler := handlers.NewNotificationHandler(db)
+	ruleHandler := handlers.NewRuleHandler(db)
+	daoHandler := handlers.NewDAOHandler(db, blockchainService, cfg)
+
+	// On-chain execution engine for passed proposals' ExecutionData. Most
+	// deployments don't run a hot wallet, so a missing GOVERNOR_PRIVKEY or
+	// DAO_EXECUTOR_CHAINS disables it with a log rather than failing startup.
+	var proposalExecutor *dao.ProposalExecutor
+	if cfg.GovernorPrivateKey == "" {
+		log.Println("GOVERNOR_PRIVKEY not set, DAO proposal execution engine disabled")
+	} else if chains, err := dao.ParseChainConfigs(cfg.DAOExecutorChains); err != nil {
+		log.Printf("Warning: invalid DAO_EXECUTOR_CHAINS, proposal execution engine disabled: %v", err)
+	} else if len(chains) == 0 {
+		log.Println("DAO_EXECUTOR_CHAINS not set, DAO proposal execution engine disabled")
+	} else {
+		executor, err := dao.NewProposalExecutor(storage.NewGormStorage(db), dao.ExecutorConfig{
+			Chains:        chains,
+			PrivateKeyHex: cfg.GovernorPrivateKey,
+			PollInterval:  time.Duration(cfg.DAOExecutorPollIntervalSeconds) * time.Second,
+			DryRun:        cfg.DAOExecutorDryRun,
+		})
+		if err != nil {
+			log.Printf("Warning: failed to initialize DAO proposal execution engine: %v", err)
+		} else {
+			proposalExecutor = executor
+		}
+	}
 	authHandler := handlers.NewAuthHandler(blockchainService)
 	analyticsHandler := handlers.NewWalletAnalyticsHandler(analyticsService)
+	reputationHandler := handlers.NewReputationHandler(reputationService)
+	telegramAuthHandler := handlers.NewTelegramAuthHandler(telegramService, cfg)
 
 	// Apply rate limiting to all API routes
 	r.Use(middleware.RateLimitMiddleware())
@@ -85,9 +179,23 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 		api.POST("/auth/civic/verify", civicHandler.VerifyGatepassHandler)
 		api.GET("/auth/civic/status", civicHandler.GetAuthStatusHandler)
 
+		// Certificate Revocation List for the mTLS device-certificate auth
+		// channel (see services.CivicAuthService.CRL). CRLs are fetched by
+		// clients/bouncers that terminate mTLS themselves, so this is public
+		// by PKI convention - it reveals only which serials are revoked.
+		api.GET("/auth/civic/crl", func(c *gin.Context) {
+			crlDER, err := civicService.CRL()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.Data(http.StatusOK, "application/pkix-crl", crlDER)
+		})
+
 		// Auth endpoints
 		api.POST("/auth/verify", authHandler.VerifyWalletSignature)
 		api.GET("/auth/nonce", authHandler.GetSignatureNonce)
+		api.POST("/auth/telegram/verify", telegramAuthHandler.VerifyTelegramLogin)
 
 		// Public firewall endpoints
 		api.POST("/firewall/tx", firewallHandler.AnalyzeTransaction)
@@ -95,6 +203,11 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 
 		// Public DAO endpoints
 		api.GET("/dao/proposals", daoHandler.GetProposals)
+		api.GET("/proposals/:id/tally", daoHandler.TallyProposal)
+
+		// Address reputation endpoints
+		api.GET("/reputation/:address", reputationHandler.GetReputation)
+		api.POST("/reputation/recompute", reputationHandler.RecomputeReputation)
 
 		// Wallet analytics endpoints
 		api.GET("/analytics/wallet/:address", analyticsHandler.GetWalletAnalytics)
@@ -119,12 +232,12 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 	web3Auth.Use(middleware.Web3AuthMiddleware(blockchainService))
 	{
 		// Report endpoints
-		web3Auth.POST("/report", reportHandler.CreateReport)
 		web3Auth.GET("/reports", reportHandler.GetReports)
 
 		// Protected DAO endpoints
 		web3Auth.POST("/dao/vote", daoHandler.CastVote)
-		web3Auth.POST("/dao/proposals", daoHandler.CreateProposal)
+		web3Auth.POST("/dao/proposals/:id/execute", daoHandler.ExecuteProposal)
+		web3Auth.POST("/dao/upgrade", daoHandler.QueueUpgrade)
 
 		// Recovery endpoints
 		web3Auth.POST("/recovery/initiate", reportHandler.InitiateRecovery)
@@ -133,6 +246,62 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 		// User profile and transaction history
 		web3Auth.GET("/transactions", firewallHandler.GetTransactions)
 		web3Auth.GET("/profile", authHandler.GetWalletProfile)
+
+		// Notification channel subscriptions (Telegram/Slack/Discord/webhook/email/n8n)
+		web3Auth.POST("/notifications/channels", notificationHandler.CreateChannel)
+		web3Auth.GET("/notifications/channels", notificationHandler.GetChannels)
+		web3Auth.DELETE("/notifications/channels/:id", notificationHandler.DeleteChannel)
+
+		// Per-wallet firewall rules, evaluated by the RuleEngine against
+		// every TransactionAnalyzed event.
+		web3Auth.POST("/rules", ruleHandler.CreateRule)
+		web3Auth.GET("/rules", ruleHandler.GetRules)
+		web3Auth.PUT("/rules/:id", ruleHandler.UpdateRule)
+		web3Auth.DELETE("/rules/:id", ruleHandler.DeleteRule)
+
+		// Real-time event stream: TransactionAnalyzed/SecurityAlertRaised/
+		// ReportCreated pushed as JSON-RPC 2.0 notifications, plus
+		// subscribe/unsubscribe/analyze calls over the same socket.
+		web3Auth.GET("/ws", wsHandler.HandleConnection)
+
+		// Device-certificate enrollment for the mTLS Civic auth channel: a
+		// wallet already proven via Web3AuthMiddleware requests a client
+		// certificate for one of its devices, then authenticates future
+		// requests by presenting it over mTLS instead of polling a gatepass.
+		web3Auth.POST("/auth/civic/device-cert", func(c *gin.Context) {
+			var req struct {
+				DeviceInfo string `json:"deviceInfo"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+				return
+			}
+
+			address := c.MustGet("address").(string)
+			certPEM, keyPEM, fingerprint, err := civicService.IssueClientCert(address, req.DeviceInfo)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"certificate": string(certPEM),
+				"privateKey":  string(keyPEM),
+				"fingerprint": fingerprint,
+			})
+		})
+	}
+
+	// Routes requiring an EIP-712 signature (X-Signature header) over the
+	// request body's address/nonce/issuedAt fields plus a hash of the whole
+	// raw body, verified by
+	// RequireSignedRequest, which also enforces nonce-based replay
+	// protection and exposes the recovered signer as authed_address.
+	signedRequest := r.Group("/api")
+	signedRequest.Use(handlers.RequireSignedRequest(db, cfg))
+	{
+		signedRequest.POST("/report", reportHandler.CreateReport)
+		signedRequest.POST("/dao/proposals", daoHandler.CreateProposal)
 	}
 
 	// Admin routes (JWT authenticated)
@@ -142,43 +311,61 @@ func SetupMainRouter(db *gorm.DB, telegramService *services.TelegramService) *gi
 		admin.GET("/reports", reportHandler.GetAllReports)
 		admin.PUT("/reports/:id/verify", reportHandler.VerifyReport)
 		admin.GET("/stats", firewallHandler.GetAdminStats)
-	}
+		admin.POST("/telegram/webhook/rotate", telegramAuthHandler.RotateWebhookSecret)
 
-	// Telegram webhook endpoint
-	// This doesn't need authentication as it's secured by the Telegram API
-	r.POST("/telegram/webhook", telegramService.GetWebhookHandler())
+		// Revokes a device certificate issued via POST /api/auth/civic/device-cert,
+		// e.g. after a lost or compromised device report.
+		admin.POST("/civic/device-cert/revoke", func(c *gin.Context) {
+			var req struct {
+				Fingerprint string `json:"fingerprint" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+				return
+			}
 
-	// Telegram account linking endpoint (requires Web3 auth)
-	web3Auth.POST("/telegram/link", func(c *gin.Context) {
-		var req struct {
-			TelegramChatID string `json:"telegram_chat_id" binding:"required"`
-		}
+			if err := civicService.RevokeDeviceCredential(req.Fingerprint); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
 
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
-			return
-		}
+			c.JSON(http.StatusOK, gin.H{"message": "Device credential revoked"})
+		})
 
-		// Get user wallet address from auth middleware
-		address, exists := c.Get("address")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
-			return
-		}
-		// Link Telegram chat to wallet with empty user details (will be updated when user interacts with the bot)
-		if err := telegramService.LinkWallet(req.TelegramChatID, address.(string), "", "", ""); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Failed to link Telegram account: " + err.Error(),
-			})
-			return
-		}
+		// Reports the config snapshot the running process currently has
+		// loaded, for diagnosing what a hot reload (see config.Watcher) did
+		// or didn't pick up. Secrets are masked by Config.Redacted.
+		admin.GET("/debug/config", func(c *gin.Context) {
+			snapshot := config.CurrentSnapshot()
+			if snapshot == nil {
+				c.JSON(http.StatusOK, gin.H{
+					"version":  0,
+					"loadedAt": nil,
+					"config":   cfg.Redacted(),
+					"note":     "hot reload is disabled; reporting the startup config",
+				})
+				return
+			}
 
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"message": "Telegram account successfully linked to wallet",
+			c.JSON(http.StatusOK, gin.H{
+				"version":  snapshot.Version,
+				"loadedAt": snapshot.LoadedAt,
+				"config":   snapshot.Config.Redacted(),
+			})
 		})
-	})
+	}
+
+	// Telegram webhook endpoint
+	// This doesn't need authentication as it's secured by the Telegram API
+	r.POST("/telegram/webhook", telegramService.GetWebhookHandler())
+
+	// Telegram account linking endpoint. The Telegram side is proven by a
+	// session JWT from POST /auth/telegram/verify rather than a
+	// client-supplied telegram_chat_id - a caller who merely knows a
+	// victim's chat ID must not be able to link (or hijack) their Telegram
+	// notifications. The older chat-ID-only /telegram/link endpoint that
+	// predated this proof has been removed for that reason.
+	web3Auth.POST("/telegram/link-session", telegramAuthHandler.LinkTelegramSession)
 
-	return r
+	return r, daoHandler, civicService, proposalExecutor
 }
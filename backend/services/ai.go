@@ -1,126 +1,84 @@
 package services
 
 import (
+	"time"
+
 	"Wallet/backend/models"
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"Wallet/backend/pkg/observability"
 )
 
-// AIService provides machine learning model access for transaction analysis
+// AIService provides transaction risk analysis. It used to POST a fixed
+// 18-slot feature vector to an external ML API; it now delegates to a
+// pluggable RiskScorer so scoring runs entirely locally and nothing about
+// the transaction leaves the process.
 type AIService struct {
-	modelURL         string
-	analyticsService *WalletAnalyticsService
+	scorer            RiskScorer
+	featureExtractor  *FeatureExtractor
+	analyticsService  *WalletAnalyticsService
+	reputationService *ReputationService
+}
+
+// SetReputationService attaches a ReputationService so
+// AnalyzeTransactionEnhanced and IsAddressBlacklisted can consult it. It's a
+// setter rather than a constructor argument because the reputation service
+// and AIService are wired up independently in routes.SetupMainRouter.
+func (s *AIService) SetReputationService(reputationService *ReputationService) {
+	s.reputationService = reputationService
 }
 
-// NewAIService creates a new AI service instance
+// NewAIService creates a new AI service backed by the local rules scorer.
+// Use NewAIServiceWithScorer to plug in an ONNXRiskScorer instead.
 func NewAIService(analyticsService *WalletAnalyticsService) *AIService {
-	// Always use the external ML API
-	modelURL := "https://ml-fraud-transaction-detection.onrender.com/predict"
+	return NewAIServiceWithScorer(analyticsService, NewRulesRiskScorer())
+}
 
+// NewAIServiceWithScorer creates an AI service backed by an explicit
+// RiskScorer, e.g. an ONNXRiskScorer loaded from a local model file.
+func NewAIServiceWithScorer(analyticsService *WalletAnalyticsService, scorer RiskScorer) *AIService {
 	return &AIService{
-		modelURL:         modelURL,
+		scorer:           scorer,
+		featureExtractor: NewFeatureExtractor(analyticsService),
 		analyticsService: analyticsService,
 	}
 }
 
-// AIModelRequest represents the request structure for AI model prediction
-type AIModelRequest struct {
-	FromAddress           string    `json:"from_address"`
-	ToAddress             string    `json:"to_address"`
-	TransactionValue      float64   `json:"transaction_value"`
-	GasPrice              float64   `json:"gas_price"`
-	IsContractInteraction bool      `json:"is_contract_interaction"`
-	AccHolder             string    `json:"acc_holder"`
-	Features              []float64 `json:"features"`
-}
-
-// AIModelResponse represents the prediction response from the AI model
-type AIModelResponse struct {
-	Risk        float64            `json:"risk_score"`
-	Explanation string             `json:"explanation"`
-	Confidence  float64            `json:"confidence"`
-	Features    map[string]float64 `json:"feature_importance"`
-}
-
-// AnalyzeTransaction calls the ML model to analyze transaction risk
+// AnalyzeTransaction scores a transaction's fraud risk using the configured
+// RiskScorer and returns a risk value in [0, 1].
 func (s *AIService) AnalyzeTransaction(tx models.Transaction) (float64, error) {
-	// Create a fixed array of 18 features as required by external ML API
-	features := make([]float64, 18)
-
-	// Set transaction value in the features array (position 13 based on test script)
-	features[13] = tx.Value
-
-	// Set gas price in the features array (position 14 based on test script)
-	gasPrice := 20.0 // Default gas price
-	features[14] = gasPrice
-
-	// Determine if this is a contract interaction
-	isContract := false
-
-	// Prepare request payload for external ML API
-	request := AIModelRequest{
-		FromAddress:           tx.FromAddress,
-		ToAddress:             tx.ToAddress,
-		TransactionValue:      tx.Value,
-		GasPrice:              gasPrice,
-		IsContractInteraction: isContract,
-		AccHolder:             tx.FromAddress,
-		Features:              features,
-	}
-
-	jsonData, err := json.Marshal(request)
+	assessment, err := s.AssessTransaction(tx)
 	if err != nil {
-		return 0, fmt.Errorf("error marshaling request: %w", err)
-	}
-
-	// Make HTTP request to ML model
-	resp, err := http.Post(s.modelURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return 0, fmt.Errorf("error calling ML model: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check if response is successful
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("ML model returned non-OK status: %d", resp.StatusCode)
-	}
-	// Parse response
-	var externalResponse struct {
-		Prediction string `json:"prediction"`
-		Type       string `json:"Type"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&externalResponse); err != nil {
-		return 0, fmt.Errorf("error decoding model response: %w", err)
-	}
-
-	// Convert external API response to our risk score format
-	var riskScore float64
-	if externalResponse.Prediction == "Fraud" {
-		riskScore = 0.85 // High risk
-	} else if externalResponse.Prediction == "Suspicious" {
-		riskScore = 0.5 // Medium risk
-	} else {
-		riskScore = 0.1 // Low risk
+		return 0, err
 	}
+	return assessment.Score, nil
+}
 
-	return riskScore, nil
+// AssessTransaction is like AnalyzeTransaction but returns the full
+// RiskAssessment (confidence and per-feature contributions), which
+// GetRiskExplanation uses to produce SHAP-style reasons instead of the three
+// canned strings the external API mapping used to produce.
+//
+// ml_fraud_call_duration_seconds times this method rather than an actual
+// network call - see the MLFraudCallDuration comment in observability's
+// newMetrics for why there's no
+// remaining HTTP round trip to wrap in a span here.
+func (s *AIService) AssessTransaction(tx models.Transaction) (RiskAssessment, error) {
+	start := time.Now()
+	defer func() {
+		observability.Default().MLFraudCallDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	features := s.featureExtractor.Extract(tx)
+	return s.scorer.Score(features)
 }
 
-// AnalyzeTransactionEnhanced performs enhanced analysis for high-value transactions
+// AnalyzeTransactionEnhanced performs enhanced analysis for high-value
+// transactions, layering extra historical checks on top of the base score.
 func (s *AIService) AnalyzeTransactionEnhanced(tx models.Transaction) (float64, error) {
-	// For enhanced analysis, we'll use both our base model and additional checks
-
-	// First get base risk score
-	baseRisk, err := s.AnalyzeTransaction(tx)
+	assessment, err := s.AssessTransaction(tx)
 	if err != nil {
-		return 0, fmt.Errorf("base analysis failed: %w", err)
+		return 0, err
 	}
-
-	// For high-value transactions, we do additional analysis
-	enhancedRisk := baseRisk
+	enhancedRisk := assessment.Score
 
 	// Get historical analytics if available
 	if s.analyticsService != nil {
@@ -137,16 +95,38 @@ func (s *AIService) AnalyzeTransactionEnhanced(tx models.Transaction) (float64,
 		}
 	}
 
-	// Cap the risk score at 1.0
-	if enhancedRisk > 1.0 {
-		enhancedRisk = 1.0
+	// Fold in the destination's decayed reputation score, if available.
+	if s.reputationService != nil {
+		if rep, err := s.reputationService.GetReputation(tx.ToAddress); err == nil {
+			enhancedRisk += rep.Score * rep.Confidence * 0.3
+		}
 	}
 
-	return enhancedRisk, nil
+	return clamp01(enhancedRisk), nil
 }
 
-// GetRiskExplanation provides a human-readable explanation for a risk score
+// GetRiskExplanation provides a human-readable explanation for a risk score,
+// citing the features that contributed most when an assessment is
+// available, in place of the three canned strings the external API mapping
+// used to produce.
 func (s *AIService) GetRiskExplanation(risk float64, tx models.Transaction) string {
+	assessment, err := s.AssessTransaction(tx)
+	if err != nil || len(assessment.TopFeatures) == 0 {
+		return genericRiskExplanation(risk)
+	}
+
+	reason := genericRiskExplanation(risk) + " Top contributing factors: "
+	for i, fc := range assessment.TopFeatures {
+		if i > 0 {
+			reason += ", "
+		}
+		reason += fc.Feature
+	}
+	reason += "."
+	return reason
+}
+
+func genericRiskExplanation(risk float64) string {
 	if risk > 0.7 {
 		return "High risk transaction detected: This address has been associated with suspicious activity."
 	} else if risk > 0.3 {
@@ -155,14 +135,25 @@ func (s *AIService) GetRiskExplanation(risk float64, tx models.Transaction) stri
 	return "Low risk transaction: No significant risk factors detected."
 }
 
-// IsAddressBlacklisted checks if an address is in the known scammer list
+// blacklistReputationThreshold is the decayed reputation score above which
+// an address is treated as blacklisted.
+const blacklistReputationThreshold = 0.6
+
+// IsAddressBlacklisted checks if an address's reputation score has crossed
+// blacklistReputationThreshold. Falls back to a small hardcoded list when no
+// ReputationService is configured (e.g. in tests or partial deployments).
 func (s *AIService) IsAddressBlacklisted(address string) (bool, error) {
-	// TODO: Implement blacklist checking against a database or external API
-	// For now, we'll just return a hardcoded value for demonstration
+	if s.reputationService != nil {
+		rep, err := s.reputationService.GetReputation(address)
+		if err != nil {
+			return false, err
+		}
+		return rep.Score >= blacklistReputationThreshold, nil
+	}
+
 	knownScamAddresses := map[string]bool{
 		"0x1234567890abcdef1234567890abcdef12345678": true,
 		"0x0987654321fedcba0987654321fedcba09876543": true,
 	}
-
 	return knownScamAddresses[address], nil
 }
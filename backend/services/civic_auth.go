@@ -1,19 +1,43 @@
 package services
 
 import (
+	"Wallet/backend/config"
+	"Wallet/backend/logging"
 	"Wallet/backend/models"
+	"Wallet/backend/pkg/observability"
+	"Wallet/backend/pkg/pki"
+	"Wallet/backend/storage"
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
-	"net/http"
+	"log"
+	"math/big"
+	"sync"
 	"time"
 	"github.com/civic/civic-pass-api/pkg/gateway"
-	"gorm.io/gorm"
+)
+
+// impossibleTravelDistanceKm and impossibleTravelWindow bound the
+// impossible_travel check in performSecurityChecks: a flag fires when two
+// consecutive logins are resolved more than impossibleTravelDistanceKm apart
+// within impossibleTravelWindow of each other, which is quicker than any
+// commercial flight could plausibly cover the same distance.
+const (
+	impossibleTravelDistanceKm = 800.0
+	impossibleTravelWindow     = 1 * time.Hour
 )
 
 type CivicAuthService struct {
-	db            *gorm.DB
+	sessions         storage.SessionStore
+	verificationLogs storage.VerificationLogStore
+	ca               *pki.CA
+	geoIP            *GeoIPLookup
+
+	// mu guards gatewayClient/config, which Reload swaps out when
+	// CivicAPIKey/CivicStage/CivicGatekeeperNetwork rotate.
+	mu            sync.RWMutex
 	gatewayClient *gateway.Client
 	config        *CivicConfig
 }
@@ -25,29 +49,79 @@ type CivicConfig struct {
 	Stage             string // "prod" or "preprod"
 }
 
-func NewCivicAuthService(db *gorm.DB, config *CivicConfig) *CivicAuthService {
+func NewCivicAuthService(sessions storage.SessionStore, verificationLogs storage.VerificationLogStore, config *CivicConfig, geoIP *GeoIPLookup) *CivicAuthService {
 	client := gateway.NewClient(config.ApiKey, config.Stage == "prod")
+
+	// The device CA backs the mTLS auth channel below. Its root key is
+	// generated fresh per-process (see pki.CA's doc comment), which is fine
+	// here: trust is rooted in the DeviceCredential table, not in the chain
+	// surviving a restart.
+	ca, err := pki.NewCA(pki.CAConfig{
+		CommonName:   "Wallet Internal Device CA",
+		Organization: "Wallet",
+		Country:      "US",
+	}, pki.CAConfigProfiles{})
+	if err != nil {
+		log.Printf("Warning: failed to initialize device CA, mTLS auth channel disabled: %v", err)
+	}
+
 	return &CivicAuthService{
-		db:            db,
-		gatewayClient: client,
-		config:        config,
+		sessions:         sessions,
+		verificationLogs: verificationLogs,
+		gatewayClient:    client,
+		config:           config,
+		ca:               ca,
+		geoIP:            geoIP,
 	}
 }
 
-// InitiateAuth starts the Civic authentication process
-func (s *CivicAuthService) InitiateAuth(userAddress string, deviceInfo string) (*models.CivicAuthSession, error) {
+// snapshot returns the currently active CivicConfig and gateway client,
+// guarding against a concurrent Reload.
+func (s *CivicAuthService) snapshot() (*CivicConfig, *gateway.Client) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config, s.gatewayClient
+}
+
+// Reload rebuilds the Civic gateway client from cfg's Civic* fields, so
+// rotating CivicAPIKey (or flipping CivicStage between "prod"/"preprod")
+// takes effect without restarting the process. The device CA and
+// already-issued DeviceCredentials are unaffected - they aren't derived
+// from cfg.
+func (s *CivicAuthService) Reload(cfg *config.Config) {
+	newConfig := &CivicConfig{
+		GatekeeperNetwork: cfg.CivicGatekeeperNetwork,
+		ChainId:           cfg.ChainID,
+		ApiKey:            cfg.CivicAPIKey,
+		Stage:             cfg.CivicStage,
+	}
+	client := gateway.NewClient(newConfig.ApiKey, newConfig.Stage == "prod")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = newConfig
+	s.gatewayClient = client
+}
+
+// InitiateAuth starts the Civic authentication process. ctx carries the
+// request's correlation ID (see middleware.CorrelationIDMiddleware) through
+// to the Civic gateway call and the structured log emitted by
+// logVerificationAttempt.
+func (s *CivicAuthService) InitiateAuth(ctx context.Context, userAddress string, deviceInfo string) (*models.CivicAuthSession, error) {
 	// Check for existing valid session
-	var existingSession models.CivicAuthSession
-	if err := s.db.Where("user_address = ? AND token_expiry > ?", userAddress, time.Now()).First(&existingSession).Error; err == nil {
-		return &existingSession, nil
+	if existingSession, err := s.sessions.GetActiveSession(userAddress); err == nil {
+		return existingSession, nil
 	}
 
 	// Create new gatepass token
-	token, err := s.gatewayClient.CreateToken(context.Background(), &gateway.CreateTokenRequest{
-		GatekeeperNetwork: s.config.GatekeeperNetwork,
-		ChainId:           s.config.ChainId,
+	civicConfig, gatewayClient := s.snapshot()
+	ctx, span := observability.Tracer().Start(ctx, "civic.gateway.create_token")
+	token, err := gatewayClient.CreateToken(ctx, &gateway.CreateTokenRequest{
+		GatekeeperNetwork: civicConfig.GatekeeperNetwork,
+		ChainId:           civicConfig.ChainId,
 		WalletAddress:     userAddress,
 	})
+	span.End()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Civic token: %v", err)
 	}
@@ -55,36 +129,45 @@ func (s *CivicAuthService) InitiateAuth(userAddress string, deviceInfo string) (
 	// Create new session with enhanced security
 	session := &models.CivicAuthSession{
 		UserAddress:       userAddress,
-		GatekeeperNetwork: s.config.GatekeeperNetwork,
+		GatekeeperNetwork: civicConfig.GatekeeperNetwork,
 		TokenExpiry:       time.Now().Add(24 * time.Hour),
 		Status:           "pending",
 		GatePass:         token.Token,
 		SecurityLevel:    1,
-		DeviceHash:      generateDeviceHash(deviceInfo),
+		DeviceHash:      generateDeviceHash(deviceInfo, ""),
 		RiskScore:       0.0,
 	}
 
-	if err := s.db.Create(session).Error; err != nil {
+	if err := s.sessions.SaveSession(session); err != nil {
 		return nil, fmt.Errorf("failed to create auth session: %v", err)
 	}
 
 	// Log the verification attempt
-	s.logVerificationAttempt(userAddress, "initial", true, deviceInfo)
+	s.logVerificationAttempt(ctx, userAddress, "initial", true, deviceInfo, 0, nil)
 
 	return session, nil
 }
 
-// VerifyGatepass validates the Civic gatepass and implements additional security measures
-func (s *CivicAuthService) VerifyGatepass(userAddress, gatepass string, deviceInfo string) (*models.CivicAuthSession, error) {
-	var session models.CivicAuthSession
-	if err := s.db.Where("user_address = ? AND gate_pass = ?", userAddress, gatepass).First(&session).Error; err != nil {
+// VerifyGatepass validates the Civic gatepass and implements additional
+// security measures. See InitiateAuth for what ctx is used for.
+func (s *CivicAuthService) VerifyGatepass(ctx context.Context, userAddress, gatepass string, deviceInfo string) (*models.CivicAuthSession, error) {
+	start := time.Now()
+	defer func() {
+		observability.Default().CivicVerifyDuration.WithLabelValues("verification").Observe(time.Since(start).Seconds())
+	}()
+
+	session, err := s.sessions.GetSessionByGatepass(userAddress, gatepass)
+	if err != nil {
 		return nil, errors.New("invalid session")
 	}
 
 	// Verify with Civic gateway
-	verified, err := s.gatewayClient.VerifyToken(context.Background(), gatepass)
+	_, gatewayClient := s.snapshot()
+	gwCtx, span := observability.Tracer().Start(ctx, "civic.gateway.verify_token")
+	verified, err := gatewayClient.VerifyToken(gwCtx, gatepass)
+	span.End()
 	if err != nil || !verified {
-		s.logVerificationAttempt(userAddress, "verification", false, deviceInfo)
+		s.logVerificationAttempt(ctx, userAddress, "verification", false, deviceInfo, 0, nil)
 		return nil, errors.New("civic verification failed")
 	}
 
@@ -93,12 +176,13 @@ func (s *CivicAuthService) VerifyGatepass(userAddress, gatepass string, deviceIn
 	if len(riskFactors) > 0 {
 		session.Flags = riskFactors
 		session.RiskScore = calculateRiskScore(riskFactors)
-		
+
 		// If risk is too high, require additional verification
 		if session.RiskScore > 0.7 {
 			session.SecurityLevel = 3
 			session.Status = "needs_additional_verification"
-			s.db.Save(&session)
+			s.sessions.SaveSession(session)
+			s.logVerificationAttempt(ctx, userAddress, "verification", false, deviceInfo, session.RiskScore, riskFactors)
 			return nil, errors.New("additional verification required due to high risk score")
 		}
 	}
@@ -106,72 +190,309 @@ func (s *CivicAuthService) VerifyGatepass(userAddress, gatepass string, deviceIn
 	// Update session status
 	session.Status = "verified"
 	session.LastVerified = time.Now()
-	if err := s.db.Save(&session).Error; err != nil {
+	if err := s.sessions.SaveSession(session); err != nil {
 		return nil, err
 	}
 
-	s.logVerificationAttempt(userAddress, "verification", true, deviceInfo)
-	return &session, nil
+	s.logVerificationAttempt(ctx, userAddress, "verification", true, deviceInfo, session.RiskScore, riskFactors)
+	return session, nil
 }
 
 // PerformSecurityChecks implements advanced security measures
 func (s *CivicAuthService) performSecurityChecks(userAddress, deviceInfo string) []string {
 	var flags []string
-	
+
 	// Check for multiple devices
-	var deviceCount int64
-	s.db.Model(&models.CivicAuthSession{}).
-		Where("user_address = ? AND device_hash != ?", userAddress, generateDeviceHash(deviceInfo)).
-		Count(&deviceCount)
-	
+	deviceCount, _ := s.sessions.CountOtherDevices(userAddress, generateDeviceHash(deviceInfo, ""))
 	if deviceCount > 2 {
 		flags = append(flags, "multiple_devices_detected")
 	}
 
 	// Check for rapid verification attempts
-	var recentAttempts int64
-	s.db.Model(&models.CivicVerificationLog{}).
-		Where("user_address = ? AND created_at > ?", userAddress, time.Now().Add(-5*time.Minute)).
-		Count(&recentAttempts)
-	
+	recentAttempts, _ := s.verificationLogs.CountRecentAttempts(userAddress, time.Now().Add(-5*time.Minute))
 	if recentAttempts > 5 {
 		flags = append(flags, "rapid_verification_attempts")
 	}
 
-	// Geographic anomaly detection
-	if geoLocation := extractGeoLocation(deviceInfo); geoLocation != "" {
-		var lastLocation string
-		s.db.Model(&models.CivicVerificationLog{}).
-			Where("user_address = ? AND geo_location != ''", userAddress).
-			Order("created_at desc").
-			Limit(1).
-			Pluck("geo_location", &lastLocation)
+	// Geographic anomaly detection: compare the resolved country+ASN (not
+	// free-form strings) against the last logged GeoPoint, and flag
+	// impossible_travel when the two points are implausibly far apart for
+	// how little time has elapsed between them.
+	if geo, ok := s.resolveGeo(deviceInfo); ok {
+		if last, hasLast, err := s.verificationLogs.LastGeoPoint(userAddress); err == nil && hasLast {
+			if last.Country != geo.Country || last.ASN != geo.ASN {
+				flags = append(flags, "location_change_detected")
+			}
 
-		if lastLocation != "" && lastLocation != geoLocation {
-			flags = append(flags, "location_change_detected")
+			elapsed := time.Since(last.RecordedAt)
+			distanceKm := HaversineKm(last.Latitude, last.Longitude, geo.Latitude, geo.Longitude)
+			if elapsed < impossibleTravelWindow && distanceKm > impossibleTravelDistanceKm {
+				flags = append(flags, "impossible_travel")
+			}
 		}
 	}
 
+	for _, flag := range flags {
+		observability.Default().CivicRiskFlagsTotal.WithLabelValues(flag).Inc()
+	}
+
 	return flags
 }
 
-// LogVerificationAttempt records authentication attempts for security analysis
-func (s *CivicAuthService) logVerificationAttempt(userAddress, verificationType string, success bool, deviceInfo string) {
-	log := &models.CivicVerificationLog{
+// resolveGeo looks up deviceInfo's reported ClientIP (see DeviceInfo's doc
+// comment on why a client can't spoof this field's trust) against geoIP,
+// reporting ok=false if no ClientIP is set or GeoIPDBPath isn't configured.
+func (s *CivicAuthService) resolveGeo(deviceInfo string) (GeoLocation, bool) {
+	ip := ParseDeviceInfo(deviceInfo).ClientIP
+	if ip == "" || s.geoIP == nil {
+		return GeoLocation{}, false
+	}
+	return s.geoIP.Lookup(ip)
+}
+
+// IssueClientCert issues a device certificate for userAddress via the
+// internal CA and records its fingerprint as a DeviceCredential, so
+// VerifyPeerCertificate can later recognize it. This is the enrollment step
+// for the mTLS auth channel: a server-to-server bouncer or power user calls
+// this once per device, then authenticates with VerifyMTLSSession on every
+// subsequent request instead of polling a Civic gatepass.
+func (s *CivicAuthService) IssueClientCert(userAddress, deviceInfo string) (certPEM, keyPEM []byte, fingerprint string, err error) {
+	if s.ca == nil {
+		return nil, nil, "", errors.New("device CA is not available")
+	}
+
+	certPEM, keyPEM, fingerprint, serialNumber, err := s.ca.IssueClientCert(userAddress)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to issue client certificate: %w", err)
+	}
+
+	cred := &models.DeviceCredential{
+		UserAddress:  userAddress,
+		DeviceInfo:   deviceInfo,
+		Fingerprint:  fingerprint,
+		SerialNumber: serialNumber.String(),
+		IssuedAt:     time.Now(),
+		ExpiresAt:    time.Now().Add(pki.DefaultProfiles.ClientAuth.Expiry),
+	}
+	if err := s.sessions.CreateDeviceCredential(cred); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to record device credential: %w", err)
+	}
+
+	return certPEM, keyPEM, fingerprint, nil
+}
+
+// VerifyPeerCertificate builds a tls.Config.VerifyPeerCertificate hook that
+// rejects a presented client certificate whose SHA-256 fingerprint isn't a
+// live (non-revoked, unexpired) DeviceCredential row. Full chain validation
+// against the CA is intentionally skipped: the CA's key isn't persisted
+// across restarts (see pkg/pki.CA), so the DeviceCredential table - not the
+// certificate chain - is this service's source of truth for which devices
+// are trusted.
+func (s *CivicAuthService) VerifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no client certificate presented")
+	}
+
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		return errors.New("client certificate has expired")
+	}
+
+	fingerprint := pki.Fingerprint(rawCerts[0])
+
+	cred, err := s.sessions.GetDeviceCredential(fingerprint)
+	if err != nil {
+		return errors.New("client certificate not recognized")
+	}
+	if cred.RevokedAt != nil {
+		return errors.New("client certificate has been revoked")
+	}
+	if time.Now().After(cred.ExpiresAt) {
+		return errors.New("device credential has expired")
+	}
+
+	return nil
+}
+
+// VerifyMTLSSession authenticates userAddress via a previously issued
+// device certificate instead of a Civic gatepass token. The caller (the
+// HTTP layer) has already terminated TLS with a VerifyPeerCertificate hook
+// built above, so this only needs to look up the resulting fingerprint, run
+// the same performSecurityChecks the gatepass path runs, and mint a
+// verified session - it never polls the Civic gateway.
+func (s *CivicAuthService) VerifyMTLSSession(ctx context.Context, userAddress, fingerprint, deviceInfo string) (*models.CivicAuthSession, error) {
+	start := time.Now()
+	defer func() {
+		observability.Default().CivicVerifyDuration.WithLabelValues("mtls").Observe(time.Since(start).Seconds())
+	}()
+
+	cred, err := s.sessions.GetDeviceCredentialForUser(userAddress, fingerprint)
+	if err != nil {
+		return nil, errors.New("device credential not found for user")
+	}
+	if cred.RevokedAt != nil {
+		return nil, errors.New("device credential has been revoked")
+	}
+	if time.Now().After(cred.ExpiresAt) {
+		return nil, errors.New("device credential has expired")
+	}
+
+	riskFactors := s.performSecurityChecks(userAddress, deviceInfo)
+	riskScore := calculateRiskScore(riskFactors)
+	if riskScore > 0.7 {
+		s.logVerificationAttempt(ctx, userAddress, "mtls", false, deviceInfo, riskScore, riskFactors)
+		return nil, errors.New("additional verification required due to high risk score")
+	}
+
+	civicConfig, _ := s.snapshot()
+	session := &models.CivicAuthSession{
+		UserAddress:       userAddress,
+		GatekeeperNetwork: civicConfig.GatekeeperNetwork,
+		TokenExpiry:       cred.ExpiresAt,
+		Status:            "verified",
+		SecurityLevel:     2,
+		DeviceHash:        fingerprint,
+		Flags:             riskFactors,
+		RiskScore:         riskScore,
+		LastVerified:      time.Now(),
+	}
+	if err := s.sessions.SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to create mTLS-verified session: %w", err)
+	}
+
+	s.logVerificationAttempt(ctx, userAddress, "mtls", true, deviceInfo, riskScore, riskFactors)
+	return session, nil
+}
+
+// ServerTLSConfig builds a *tls.Config for a dedicated mTLS listener: a
+// freshly issued server certificate from the same in-process device CA,
+// plus VerifyPeerCertificate wired in to reject any client certificate
+// whose DeviceCredential isn't live. A reverse proxy terminating mTLS on
+// this service's behalf should call VerifyPeerCertificate directly instead.
+func (s *CivicAuthService) ServerTLSConfig(commonName string) (*tls.Config, error) {
+	if s.ca == nil {
+		return nil, errors.New("device CA is not available")
+	}
+
+	certPEM, keyPEM, _, _, err := s.ca.IssueServerCert(commonName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue server certificate: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates:          []tls.Certificate{cert},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: s.VerifyPeerCertificate,
+	}, nil
+}
+
+// RevokeDeviceCredential marks fingerprint's DeviceCredential revoked so
+// VerifyPeerCertificate and VerifyMTLSSession stop trusting it, and it's
+// included in the next CRL.
+func (s *CivicAuthService) RevokeDeviceCredential(fingerprint string) error {
+	revoked, err := s.sessions.RevokeDeviceCredential(fingerprint, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke device credential: %w", err)
+	}
+	if !revoked {
+		return errors.New("device credential not found or already revoked")
+	}
+	return nil
+}
+
+// CRL returns a DER-encoded Certificate Revocation List covering every
+// revoked DeviceCredential, for a revocation endpoint clients can poll
+// instead of relying solely on VerifyPeerCertificate's live DB lookup.
+func (s *CivicAuthService) CRL() ([]byte, error) {
+	if s.ca == nil {
+		return nil, errors.New("device CA is not available")
+	}
+
+	revokedCreds, err := s.sessions.ListRevokedDeviceCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load revoked credentials: %w", err)
+	}
+
+	revoked := make([]pki.RevokedCert, 0, len(revokedCreds))
+	for _, cred := range revokedCreds {
+		serial, ok := new(big.Int).SetString(cred.SerialNumber, 10)
+		if !ok {
+			continue
+		}
+		revoked = append(revoked, pki.RevokedCert{SerialNumber: serial, RevokedAt: *cred.RevokedAt})
+	}
+
+	return s.ca.GenerateCRL(revoked)
+}
+
+// LogVerificationAttempt records authentication attempts for security
+// analysis, both as a CivicVerificationLog row and as a structured
+// "civic.verify" log event carrying ctx's correlation ID (see
+// middleware.CorrelationIDMiddleware). riskScore/flags are whatever
+// performSecurityChecks has produced so far and are zero/nil for outcomes
+// decided before security checks run (e.g. a failed gateway verification).
+// The CivicVerificationLog model has no correlation-ID or risk-score column
+// in this tree, so the structured event is the only place these can be
+// joined against a verification outcome today.
+func (s *CivicAuthService) logVerificationAttempt(ctx context.Context, userAddress, verificationType string, success bool, deviceInfo string, riskScore float64, flags []string) {
+	info := ParseDeviceInfo(deviceInfo)
+	geo, _ := s.resolveGeo(deviceInfo)
+
+	entry := &models.CivicVerificationLog{
 		UserAddress:      userAddress,
 		VerificationType: verificationType,
-		Success:         success,
-		DeviceInfo:      deviceInfo,
-		GeoLocation:     extractGeoLocation(deviceInfo),
-		IPAddress:       extractIPAddress(deviceInfo),
+		Success:          success,
+		DeviceInfo:       deviceInfo,
+		IPAddress:        info.ClientIP,
+		GeoCountry:       geo.Country,
+		GeoCity:          geo.City,
+		GeoASN:           geo.ASN,
+		GeoLatitude:      geo.Latitude,
+		GeoLongitude:     geo.Longitude,
+	}
+	s.verificationLogs.CreateLog(entry)
+
+	outcome := "blocked"
+	if success {
+		outcome = "verified"
+	}
+	observability.Default().CivicVerifyTotal.WithLabelValues(outcome).Inc()
+	if success && verificationType != "initial" {
+		observability.Default().CivicActiveSessions.Inc()
 	}
-	s.db.Create(log)
+	logging.FromContext(ctx).Info("civic.verify",
+		"event", "civic.verify",
+		"user_address", userAddress,
+		"verification_type", verificationType,
+		"risk_score", riskScore,
+		"flags", flags,
+		"geo_country", geo.Country,
+		"geo_asn", geo.ASN,
+		"outcome", outcome,
+	)
 }
 
 // Helper functions
-func generateDeviceHash(deviceInfo string) string {
-	// Implement device fingerprinting logic
-	return "hash_" + deviceInfo // Replace with actual hashing
+
+// generateDeviceHash derives a stable identifier for a device. When the
+// caller already has an mTLS certificate fingerprint, that fingerprint is a
+// stronger, collision-resistant identifier and is returned as-is; deviceInfo
+// is only hashed as a fallback for the plain gatepass path, which has no
+// certificate to key off of - see DeviceInfo.Fingerprint for what's hashed.
+func generateDeviceHash(deviceInfo string, fingerprint string) string {
+	if fingerprint != "" {
+		return fingerprint
+	}
+	return ParseDeviceInfo(deviceInfo).Fingerprint()
 }
 
 func calculateRiskScore(flags []string) float64 {
@@ -184,17 +505,9 @@ func calculateRiskScore(flags []string) float64 {
 			score += 0.4
 		case "location_change_detected":
 			score += 0.2
+		case "impossible_travel":
+			score += 0.5
 		}
 	}
 	return score
 }
-
-func extractGeoLocation(deviceInfo string) string {
-	// Implement geo-location extraction
-	return "US" // Replace with actual implementation
-}
-
-func extractIPAddress(deviceInfo string) string {
-	// Implement IP extraction
-	return "127.0.0.1" // Replace with actual implementation
-}
@@ -0,0 +1,311 @@
+// Package dao runs the on-chain side of DAO governance: submitting a passed
+// proposal's ExecutionData to its target chain's governance contract, once
+// handlers.DAOHandler.TallyExpiredProposals (or TallyProposal) has already
+// flipped Status to "passed". Everything upstream of that - voting,
+// quorum/threshold tallying, the typed param_change/treasury_spend/upgrade
+// Payload executors - stays in handlers.DAOHandler; this package only
+// concerns itself with ExecutionData, the free-form ABI-encoded calldata
+// blob a proposal carries for direct governance contract calls.
+package dao
+
+import (
+	"Wallet/backend/models"
+	"Wallet/backend/storage"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ChainConfig is one chain a ProposalExecutor can submit governance
+// transactions to, keyed by EIP-155 chain ID in ExecutorConfig.Chains.
+type ChainConfig struct {
+	RPCURL           string `json:"rpcURL"`
+	GovernorContract string `json:"governorContract"`
+}
+
+// ParseChainConfigs decodes raw (a JSON object mapping a chain ID string to
+// a ChainConfig, e.g. {"11155111": {"rpcURL": "...", "governorContract":
+// "0x..."}}) - the shape of config.Config.DAOExecutorChains.
+func ParseChainConfigs(raw string) (map[int64]ChainConfig, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var byString map[string]ChainConfig
+	if err := json.Unmarshal([]byte(raw), &byString); err != nil {
+		return nil, fmt.Errorf("invalid chain config JSON: %w", err)
+	}
+
+	chains := make(map[int64]ChainConfig, len(byString))
+	for idStr, cfg := range byString {
+		id, err := parseChainID(idStr)
+		if err != nil {
+			return nil, err
+		}
+		chains[id] = cfg
+	}
+	return chains, nil
+}
+
+func parseChainID(s string) (int64, error) {
+	id, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid chain ID %q", s)
+	}
+	return id.Int64(), nil
+}
+
+// executorRetries/executorRetryBackoff mirror
+// services.notificationRetries/notificationRetryBackoff: attempt N waits
+// executorRetryBackoff * 2^(N-1).
+const (
+	executorRetries      = 3
+	executorRetryBackoff = 2 * time.Second
+)
+
+// ExecutorConfig configures a ProposalExecutor.
+type ExecutorConfig struct {
+	// Chains maps a chain ID to the RPC endpoint and governance contract
+	// ProposalExecutor submits to for proposals targeting that chain.
+	Chains map[int64]ChainConfig
+	// PrivateKeyHex is the governor hot wallet's key (config.Config's
+	// GOVERNOR_PRIVKEY - loaded the same way JWTSecret is, as a raw env
+	// value rather than a file/KMS reference), used to sign every submitted
+	// transaction. It never appears in logs or API responses.
+	PrivateKeyHex string
+	// PollInterval is how often Start calls PollOnce.
+	PollInterval time.Duration
+	// DryRun simulates every execution via eth_call without ever signing or
+	// broadcasting a transaction - regardless of DryRun, submit always
+	// eth_calls first, since a reverting call isn't worth paying gas for.
+	DryRun bool
+}
+
+// ProposalExecutor polls a storage.DAOStore for "passed" proposals carrying
+// ExecutionData and submits them as transactions to their target chain's
+// governance contract. Status moves passed -> executing -> executed; a
+// proposal claimed as "executing" that fails every retry is released back to
+// "passed" so the next poll picks it up again, and a proposal already moved
+// past "passed" by a concurrent poll (or executor instance sharing the same
+// store) is skipped rather than resubmitted - this is the idempotency guard
+// the request calls for, though it's a best-effort claim/release rather than
+// an atomic compare-and-swap, since storage.DAOStore has no conditional
+// update primitive.
+type ProposalExecutor struct {
+	store storage.DAOStore
+	cfg   ExecutorConfig
+	key   *ecdsa.PrivateKey
+	from  common.Address
+
+	mu      sync.Mutex
+	clients map[int64]*ethclient.Client
+}
+
+// NewProposalExecutor builds a ProposalExecutor from cfg, deriving the
+// signing address from cfg.PrivateKeyHex.
+func NewProposalExecutor(store storage.DAOStore, cfg ExecutorConfig) (*ProposalExecutor, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.PrivateKeyHex, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid governor private key: %w", err)
+	}
+
+	return &ProposalExecutor{
+		store:   store,
+		cfg:     cfg,
+		key:     key,
+		from:    crypto.PubkeyToAddress(key.PublicKey),
+		clients: make(map[int64]*ethclient.Client),
+	}, nil
+}
+
+// Start calls PollOnce on cfg.PollInterval until ctx is canceled.
+func (e *ProposalExecutor) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.PollOnce(ctx); err != nil {
+				log.Printf("ProposalExecutor: poll failed: %v", err)
+			}
+		}
+	}
+}
+
+// PollOnce executes every proposal that's "passed" and carries non-empty
+// ExecutionData, logging (rather than aborting the rest of the batch on) any
+// single proposal's failure.
+func (e *ProposalExecutor) PollOnce(ctx context.Context) error {
+	proposals, err := e.store.ListProposals()
+	if err != nil {
+		return fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	for i := range proposals {
+		p := proposals[i]
+		if p.Status != "passed" || p.ExecutionData == "" {
+			continue
+		}
+		if err := e.execute(ctx, &p); err != nil {
+			log.Printf("ProposalExecutor: proposal %d execution failed: %v", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// execute claims proposal (the idempotency guard described on
+// ProposalExecutor), submits its ExecutionData with retry/backoff, and
+// records the tx hash or releases the claim on failure.
+func (e *ProposalExecutor) execute(ctx context.Context, proposal *models.DAOProposal) error {
+	// Re-read and re-check Status rather than trusting the ListProposals
+	// snapshot, which can be stale by the time execute runs.
+	fresh, err := e.store.GetProposal(proposal.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reload proposal: %w", err)
+	}
+	if fresh.Status != "passed" {
+		return nil
+	}
+
+	fresh.Status = "executing"
+	if err := e.store.SaveProposal(fresh); err != nil {
+		return fmt.Errorf("failed to claim proposal for execution: %w", err)
+	}
+
+	txHash, err := e.submitWithRetry(ctx, fresh)
+	if err != nil {
+		fresh.Status = "passed"
+		if saveErr := e.store.SaveProposal(fresh); saveErr != nil {
+			log.Printf("ProposalExecutor: failed to release claim on proposal %d: %v", fresh.ID, saveErr)
+		}
+		return fmt.Errorf("failed to submit execution tx: %w", err)
+	}
+
+	fresh.Status = "executed"
+	fresh.ExecutionTxHash = txHash
+	if err := e.store.SaveProposal(fresh); err != nil {
+		return fmt.Errorf("executed proposal %d (tx %s) but failed to record it: %w", fresh.ID, txHash, err)
+	}
+	return nil
+}
+
+// submitWithRetry attempts submit up to executorRetries times with
+// exponential backoff.
+func (e *ProposalExecutor) submitWithRetry(ctx context.Context, proposal *models.DAOProposal) (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= executorRetries; attempt++ {
+		txHash, err := e.submit(ctx, proposal)
+		if err == nil {
+			return txHash, nil
+		}
+		lastErr = err
+
+		if attempt < executorRetries {
+			select {
+			case <-time.After(executorRetryBackoff * time.Duration(1<<(attempt-1))):
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	}
+	return "", lastErr
+}
+
+// submit eth_calls proposal's ExecutionData against its chain's
+// GovernorContract to simulate it, then - unless cfg.DryRun - signs and
+// broadcasts it as a transaction.
+func (e *ProposalExecutor) submit(ctx context.Context, proposal *models.DAOProposal) (string, error) {
+	chainCfg, ok := e.cfg.Chains[proposal.ChainID]
+	if !ok {
+		return "", fmt.Errorf("no ChainConfig configured for chain %d", proposal.ChainID)
+	}
+
+	client, err := e.clientFor(proposal.ChainID, chainCfg)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := decodeExecutionData(proposal.ExecutionData)
+	if err != nil {
+		return "", err
+	}
+	governor := common.HexToAddress(chainCfg.GovernorContract)
+
+	if _, err := client.CallContract(ctx, ethereum.CallMsg{From: e.from, To: &governor, Data: data}, nil); err != nil {
+		return "", fmt.Errorf("eth_call simulation reverted: %w", err)
+	}
+	if e.cfg.DryRun {
+		return "0x0000000000000000000000000000000000000000000000000000000000000000 (dry run, not broadcast)", nil
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, e.from)
+	if err != nil {
+		return "", fmt.Errorf("failed to read nonce: %w", err)
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: e.from, To: &governor, Data: data})
+	if err != nil {
+		return "", fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &governor,
+		Value:    big.NewInt(0),
+		Gas:      gasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	})
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(proposal.ChainID)), e.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
+	}
+
+	return signedTx.Hash().Hex(), nil
+}
+
+// clientFor lazily dials and caches an *ethclient.Client per chain ID.
+func (e *ProposalExecutor) clientFor(chainID int64, chainCfg ChainConfig) (*ethclient.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if client, ok := e.clients[chainID]; ok {
+		return client, nil
+	}
+	client, err := ethclient.Dial(chainCfg.RPCURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial chain %d RPC: %w", chainID, err)
+	}
+	e.clients[chainID] = client
+	return client, nil
+}
+
+// decodeExecutionData hex-decodes a DAOProposal's ExecutionData field,
+// tolerating an optional "0x" prefix.
+func decodeExecutionData(raw string) ([]byte, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid executionData hex: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// VotePayload is the EIP-712 typed-data payload a wallet signs to cast a DAO vote.
+type VotePayload struct {
+	ProposalID uint64
+	VoteType   string
+	Voter      string
+}
+
+// voteTypedData builds the EIP-712 typed-data document for a VotePayload under
+// the "WalletFirewall" domain, the same domain used for other signed requests.
+func voteTypedData(chainID int64, payload VotePayload) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Vote": []apitypes.Type{
+				{Name: "proposalId", Type: "uint256"},
+				{Name: "voteType", Type: "string"},
+				{Name: "voter", Type: "address"},
+			},
+		},
+		PrimaryType: "Vote",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "WalletFirewall",
+			Version: "1",
+			ChainId: math.NewHexOrDecimal256(chainID),
+		},
+		Message: apitypes.TypedDataMessage{
+			"proposalId": fmt.Sprintf("%d", payload.ProposalID),
+			"voteType":   payload.VoteType,
+			"voter":      payload.Voter,
+		},
+	}
+}
+
+// RecoverVoteSigner verifies the EIP-712 signature over a vote payload and
+// returns the address that signed it, or an error if the signature is
+// malformed or doesn't recover to a valid address. Handlers must reject
+// votes whose recovered address disagrees with the client-declared voter.
+func RecoverVoteSigner(chainID int64, payload VotePayload, signatureHex string) (string, error) {
+	typedData := voteTypedData(chainID, payload)
+
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return "", fmt.Errorf("failed to hash domain: %w", err)
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash message: %w", err)
+	}
+
+	digest := crypto.Keccak256(append([]byte("\x19\x01"), append(domainSeparator, messageHash...)...))
+
+	sig, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(sig) != 65 {
+		return "", fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	// go-ethereum expects the recovery id to be 0 or 1; wallets commonly produce 27 or 28.
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return "", fmt.Errorf("failed to recover public key: %w", err)
+	}
+
+	return crypto.PubkeyToAddress(*pubKey).Hex(), nil
+}
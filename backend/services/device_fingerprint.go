@@ -0,0 +1,88 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DeviceInfo is the structured fingerprint a client reports on every Civic
+// auth call, JSON-encoded into the deviceInfo string every CivicAuthService
+// method takes (InitiateAuth, VerifyGatepass, VerifyMTLSSession). ClientIP
+// isn't something a client can be trusted to self-report; it's expected to
+// be filled in server-side (see ExtractClientIP) by whatever builds the
+// JSON blob before calling into CivicAuthService - there's no HTTP handler
+// in this tree that does so yet (CivicAuthHandler is referenced by
+// routes.go but never defined here).
+type DeviceInfo struct {
+	UserAgent        string `json:"user_agent"`
+	AcceptLanguage   string `json:"accept_language"`
+	ScreenWidth      int    `json:"screen_width"`
+	ScreenHeight     int    `json:"screen_height"`
+	ScreenColorDepth int    `json:"screen_color_depth"`
+	CanvasHash       string `json:"canvas_hash"`
+	JA3              string `json:"ja3"`
+	ClientIP         string `json:"client_ip"`
+}
+
+// ParseDeviceInfo decodes raw (the deviceInfo string CivicAuthService's
+// methods take) into a DeviceInfo. A raw value that isn't valid JSON - e.g.
+// a caller still passing a free-form string - decodes to the zero DeviceInfo
+// rather than erroring, so existing callers degrade to an all-empty
+// fingerprint instead of failing outright.
+func ParseDeviceInfo(raw string) DeviceInfo {
+	var info DeviceInfo
+	_ = json.Unmarshal([]byte(raw), &info)
+	return info
+}
+
+// Fingerprint computes a stable SHA-256 hash over a canonicalized subset of
+// d's fields - the ones that are both stable across a device's sessions and
+// resistant to casual spoofing (JA3 in particular requires controlling the
+// TLS handshake, not just a JSON payload). ClientIP is deliberately excluded
+// since it legitimately changes session to session (see
+// location_change_detected) and shouldn't affect whether two requests are
+// treated as the same device.
+func (d DeviceInfo) Fingerprint() string {
+	canonical := strings.Join([]string{
+		d.UserAgent,
+		d.AcceptLanguage,
+		strconv.Itoa(d.ScreenWidth),
+		strconv.Itoa(d.ScreenHeight),
+		strconv.Itoa(d.ScreenColorDepth),
+		d.CanvasHash,
+		d.JA3,
+	}, "|")
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtractClientIP returns the real client IP from an X-Forwarded-For header
+// value, walking the hop chain from the right and skipping entries that are
+// known trusted proxies, falling back to remoteAddr (a net/http
+// Request.RemoteAddr-style "host:port" or bare host) if xForwardedFor is
+// empty or every hop is trusted.
+func ExtractClientIP(xForwardedFor, remoteAddr string, trustedProxies []string) string {
+	trusted := make(map[string]bool, len(trustedProxies))
+	for _, p := range trustedProxies {
+		trusted[p] = true
+	}
+
+	if xForwardedFor != "" {
+		hops := strings.Split(xForwardedFor, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if ip != "" && !trusted[ip] {
+				return ip
+			}
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
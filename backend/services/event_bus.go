@@ -0,0 +1,86 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types published on an EventBus by FirewallHandler and ReportHandler.
+const (
+	EventTransactionAnalyzed = "TransactionAnalyzed"
+	EventReportCreated       = "ReportCreated"
+	EventSecurityAlertRaised = "SecurityAlertRaised"
+)
+
+// Event is a single message published on an EventBus. Payload carries
+// event-specific fields (e.g. "risk", "to_address" for TransactionAnalyzed),
+// consumed by subscribers such as RuleEngine.
+type Event struct {
+	Type          string
+	WalletAddress string
+	Payload       map[string]interface{}
+	Timestamp     time.Time
+}
+
+// EventHandler processes a published Event.
+type EventHandler func(Event)
+
+// RemotePublisher lets an EventBus additionally fan events out to an
+// external pub/sub backend (NATS, Redis Streams, ...). No implementation
+// ships in this package; wire one in with SetRemotePublisher when such a
+// backend is available, so in-process subscribers work with zero setup.
+type RemotePublisher interface {
+	Publish(event Event) error
+}
+
+// EventBus is an in-process pub/sub hub that FirewallHandler and
+// ReportHandler publish domain events to, and RuleEngine subscribes to in
+// order to evaluate per-wallet rules.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]EventHandler
+	remote   RemotePublisher
+}
+
+// NewEventBus creates an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[string][]EventHandler)}
+}
+
+// SetRemotePublisher attaches an external backend that every Publish call
+// also forwards the event to, in addition to local subscribers.
+func (b *EventBus) SetRemotePublisher(remote RemotePublisher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remote = remote
+}
+
+// Subscribe registers handler to run for every Event of the given type.
+func (b *EventBus) Subscribe(eventType string, handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish fans event out to every subscriber of its Type, each in its own
+// goroutine so a slow or failing subscriber can't block the others or the
+// caller, and to the remote backend if one is attached.
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := append([]EventHandler(nil), b.handlers[event.Type]...)
+	remote := b.remote
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go handler(event)
+	}
+
+	if remote != nil {
+		go func() {
+			if err := remote.Publish(event); err != nil {
+				log.Printf("EventBus: remote publish failed for %s: %v", event.Type, err)
+			}
+		}()
+	}
+}
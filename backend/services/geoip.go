@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoIPLookup resolves an IP to a country/city/ASN/coordinates via a
+// MaxMind GeoLite2 database (config.Config.GeoIPDBPath). A nil/unopened
+// reader (GeoIPDBPath unset) makes Lookup always report ok=false, so
+// CivicAuthService degrades to skipping geo-based checks rather than
+// failing when no database is configured - the same fallback shape
+// AIService used for a missing RiskModelPath.
+type GeoIPLookup struct {
+	mu     sync.RWMutex
+	reader *geoip2.Reader
+}
+
+// GeoLocation is a single IP's resolved location.
+type GeoLocation struct {
+	Country   string
+	City      string
+	ASN       string
+	Latitude  float64
+	Longitude float64
+}
+
+// NewGeoIPLookup opens the GeoLite2 database at dbPath. An empty dbPath
+// returns a GeoIPLookup whose Lookup always reports ok=false, rather than an
+// error, since running without geo-based checks is a valid deployment
+// choice (see GeoIPLookup's doc comment).
+func NewGeoIPLookup(dbPath string) (*GeoIPLookup, error) {
+	if dbPath == "" {
+		return &GeoIPLookup{}, nil
+	}
+	reader, err := geoip2.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoLite2 database at %q: %w", dbPath, err)
+	}
+	return &GeoIPLookup{reader: reader}, nil
+}
+
+// Close releases the underlying database's memory-mapped file.
+func (g *GeoIPLookup) Close() error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.reader == nil {
+		return nil
+	}
+	return g.reader.Close()
+}
+
+// Lookup resolves ipStr to a GeoLocation. The City lookup must succeed, so
+// dbPath has to point at a database with City data; a database that can't
+// answer it (e.g. a GeoLite2-ASN database on its own) makes every Lookup
+// report ok=false. ASN comes from a second, ASN lookup against the same
+// database - if that one fails, ASN is left empty rather than failing the
+// whole lookup, since Country/City/coordinates are still useful on their
+// own for location_change_detected and impossible_travel.
+func (g *GeoIPLookup) Lookup(ipStr string) (GeoLocation, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.reader == nil {
+		return GeoLocation{}, false
+	}
+
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return GeoLocation{}, false
+	}
+
+	city, err := g.reader.City(ip)
+	if err != nil {
+		return GeoLocation{}, false
+	}
+
+	loc := GeoLocation{
+		Country:   city.Country.IsoCode,
+		City:      city.City.Names["en"],
+		Latitude:  city.Location.Latitude,
+		Longitude: city.Location.Longitude,
+	}
+
+	if asn, err := g.reader.ASN(ip); err == nil && asn.AutonomousSystemNumber != 0 {
+		loc.ASN = strconv.FormatUint(uint64(asn.AutonomousSystemNumber), 10)
+	}
+
+	return loc, true
+}
+
+// earthRadiusKm is the mean radius used by HaversineKm, matching the
+// constant most GIS libraries use for a great-circle approximation.
+const earthRadiusKm = 6371.0
+
+// HaversineKm returns the great-circle distance in kilometers between two
+// lat/lon points, for CivicAuthService's impossible_travel check.
+func HaversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusKm * c
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"Wallet/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationEvent is the payload every Notifier implementation receives.
+// It's deliberately looser than models.SecurityAlert / models.Report so a
+// sink doesn't need to know which domain event triggered it.
+type NotificationEvent struct {
+	Type      string // e.g. "suspicious_transaction", "scam_report"
+	Severity  string // "low", "medium", "high", "critical"
+	Title     string
+	Details   string
+	Timestamp time.Time
+}
+
+// severityRank orders severities so Dispatch can compare an event's
+// severity against a channel's MinSeverity. Unknown severities rank lowest.
+var severityRank = map[string]int{
+	"low":      0,
+	"medium":   1,
+	"high":     2,
+	"critical": 3,
+}
+
+func meetsMinSeverity(eventSeverity, minSeverity string) bool {
+	return severityRank[eventSeverity] >= severityRank[minSeverity]
+}
+
+// Notifier is a pluggable outbound alert sink. Send should respect ctx
+// cancellation and return an error for NotificationService to retry on.
+type Notifier interface {
+	// Type returns the NotificationChannel.ChannelType this Notifier handles.
+	Type() string
+	// Send delivers event to channel.Target, using channel.Secret for any
+	// channel-specific credentials.
+	Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error
+}
+
+// notificationRetries is how many attempts Dispatch gives each sink before
+// giving up and writing a NotificationDeadLetter row.
+const notificationRetries = 3
+
+// notificationRetryBackoff is the base delay between retries; attempt N
+// waits notificationRetryBackoff * 2^(N-1).
+const notificationRetryBackoff = 500 * time.Millisecond
+
+// NotificationService fans domain events (suspicious transactions, scam
+// reports, ...) out to every enabled NotificationChannel a wallet has
+// registered, via the Notifier registered for that channel's type.
+type NotificationService struct {
+	db        *gorm.DB
+	notifiers map[string]Notifier
+}
+
+// NewNotificationService creates a notification service with no notifiers
+// registered; callers register the sinks they want via Register.
+func NewNotificationService(db *gorm.DB) *NotificationService {
+	return &NotificationService{
+		db:        db,
+		notifiers: make(map[string]Notifier),
+	}
+}
+
+// Register adds a Notifier to the registry, keyed by its Type().
+func (n *NotificationService) Register(notifier Notifier) {
+	n.notifiers[notifier.Type()] = notifier
+}
+
+// Dispatch loads walletAddress's enabled NotificationChannel rows, filters
+// them to those whose MinSeverity the event meets, and delivers the event
+// to each concurrently. Each sink is retried independently with backoff;
+// a sink that still fails after notificationRetries is recorded in
+// NotificationDeadLetter rather than blocking or failing the others.
+func (n *NotificationService) Dispatch(ctx context.Context, walletAddress string, event NotificationEvent) {
+	var channels []models.NotificationChannel
+	if err := n.db.Where("wallet_address = ? AND enabled = ?", walletAddress, true).Find(&channels).Error; err != nil {
+		log.Printf("NotificationService: failed to load channels for %s: %v", walletAddress, err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, channel := range channels {
+		if !meetsMinSeverity(event.Severity, channel.MinSeverity) {
+			continue
+		}
+		notifier, ok := n.notifiers[channel.ChannelType]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(channel models.NotificationChannel, notifier Notifier) {
+			defer wg.Done()
+			n.sendWithRetry(ctx, notifier, channel, event)
+		}(channel, notifier)
+	}
+	wg.Wait()
+}
+
+// sendWithRetry attempts notifier.Send up to notificationRetries times with
+// exponential backoff, writing a NotificationDeadLetter row if every
+// attempt fails.
+func (n *NotificationService) sendWithRetry(ctx context.Context, notifier Notifier, channel models.NotificationChannel, event NotificationEvent) {
+	var lastErr error
+	for attempt := 1; attempt <= notificationRetries; attempt++ {
+		if err := notifier.Send(ctx, channel, event); err == nil {
+			return
+		} else {
+			lastErr = err
+		}
+
+		if attempt < notificationRetries {
+			select {
+			case <-time.After(notificationRetryBackoff * time.Duration(1<<(attempt-1))):
+			case <-ctx.Done():
+				lastErr = ctx.Err()
+				attempt = notificationRetries
+			}
+		}
+	}
+
+	payload, _ := json.Marshal(event)
+	deadLetter := models.NotificationDeadLetter{
+		ChannelID:   channel.ID,
+		ChannelType: channel.ChannelType,
+		EventType:   event.Type,
+		Payload:     string(payload),
+		Error:       lastErr.Error(),
+		Attempts:    notificationRetries,
+		FailedAt:    time.Now(),
+	}
+	if err := n.db.Create(&deadLetter).Error; err != nil {
+		log.Printf("NotificationService: failed to record dead letter for channel %d: %v", channel.ID, err)
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+
+	"Wallet/backend/config"
+	"Wallet/backend/models"
+)
+
+// EmailNotifier delivers NotificationEvents over SMTP. channel.Target is the
+// recipient address; the SMTP server itself comes from config.Config, since
+// it's shared infrastructure rather than something each channel configures.
+type EmailNotifier struct {
+	cfg *config.Config
+}
+
+// NewEmailNotifier creates an SMTP sink using the backend's configured mail server.
+func NewEmailNotifier(cfg *config.Config) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (e *EmailNotifier) Type() string {
+	return "email"
+}
+
+// Send emails event to channel.Target.
+func (e *EmailNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	if e.cfg.SMTPHost == "" {
+		return fmt.Errorf("SMTP is not configured")
+	}
+
+	subject := fmt.Sprintf("[%s] %s", event.Severity, event.Title)
+	body := fmt.Sprintf("To: %s\r\nFrom: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		channel.Target, e.cfg.SMTPFrom, subject, event.Details)
+
+	addr := fmt.Sprintf("%s:%d", e.cfg.SMTPHost, e.cfg.SMTPPort)
+	var auth smtp.Auth
+	if e.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", e.cfg.SMTPUsername, e.cfg.SMTPPassword, e.cfg.SMTPHost)
+	}
+
+	return smtp.SendMail(addr, auth, e.cfg.SMTPFrom, []string{channel.Target}, []byte(body))
+}
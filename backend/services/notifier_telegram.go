@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"Wallet/backend/models"
+)
+
+// TelegramNotifier delivers NotificationEvents over the existing Telegram
+// bot, reusing the same SendMessage call NotifySecurityAlert/NotifyScamReport
+// used to make directly. channel.Target is the Telegram chat ID.
+type TelegramNotifier struct {
+	telegramService *TelegramService
+}
+
+// NewTelegramNotifier creates a Telegram sink for NotificationService.
+func NewTelegramNotifier(telegramService *TelegramService) *TelegramNotifier {
+	return &TelegramNotifier{telegramService: telegramService}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (t *TelegramNotifier) Type() string {
+	return "telegram"
+}
+
+// Send posts event as a formatted Telegram message to channel.Target.
+func (t *TelegramNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	chatID, err := strconv.ParseInt(channel.Target, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram chat id %q: %w", channel.Target, err)
+	}
+
+	message := fmt.Sprintf("⚠️ <b>%s</b> ⚠️\n\n<b>Severity:</b> %s\n<b>Details:</b> %s",
+		event.Title, event.Severity, event.Details)
+
+	return t.telegramService.SendMessage(chatID, message)
+}
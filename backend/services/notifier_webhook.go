@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"Wallet/backend/models"
+)
+
+// DisallowedTargetIP reports whether ip must never be dialed as a
+// notification channel target - the cloud metadata endpoint, a
+// cluster-internal service, or localhost. Shared by
+// handlers.validateChannelTarget (checked once, at CreateChannel time) and
+// ssrfSafeClient's dialer below (checked again at send time), since a
+// hostname that resolved to a public IP when the channel was created can be
+// repointed at a disallowed one before the next send - DNS rebinding.
+func DisallowedTargetIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast()
+}
+
+// ssrfSafeClient is the HTTP client postJSON sends through, so it covers the
+// webhook, slack, discord and n8n sinks (EmailNotifier and TelegramNotifier
+// don't use it). Its Transport resolves the target host itself and dials
+// only an IP DisallowedTargetIP doesn't flag, rather than trusting
+// net.Dialer to connect wherever DNS currently points.
+var ssrfSafeClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: dialValidatedTarget,
+	},
+}
+
+// dialValidatedTarget resolves addr's host, rejects any candidate IP
+// DisallowedTargetIP flags, and dials the first allowed one directly - so
+// the connection actually goes where it was validated to go, instead of
+// wherever a second DNS lookup inside net.Dialer happens to resolve to.
+func dialValidatedTarget(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+
+	var dialer net.Dialer
+	for _, ip := range ips {
+		if DisallowedTargetIP(ip) {
+			continue
+		}
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+	}
+	return nil, fmt.Errorf("%s resolves only to disallowed addresses", host)
+}
+
+// postJSON POSTs body as JSON to url with the given extra headers, and
+// treats any non-2xx response as a delivery failure so NotificationService
+// retries it.
+func postJSON(ctx context.Context, url string, body interface{}, headers map[string]string) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := ssrfSafeClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned non-2xx status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookNotifier posts the raw event as JSON to an arbitrary URL. channel.Target
+// is the URL; channel.Secret, if set, is sent as the X-Webhook-Secret header
+// so the receiver can verify the request came from us.
+type WebhookNotifier struct{}
+
+// NewWebhookNotifier creates a generic webhook sink.
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (w *WebhookNotifier) Type() string {
+	return "webhook"
+}
+
+// Send posts event as JSON to channel.Target.
+func (w *WebhookNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	headers := map[string]string{}
+	if channel.Secret != "" {
+		headers["X-Webhook-Secret"] = channel.Secret
+	}
+	return postJSON(ctx, channel.Target, event, headers)
+}
+
+// SlackNotifier posts event to a Slack incoming webhook URL.
+type SlackNotifier struct{}
+
+// NewSlackNotifier creates a Slack incoming-webhook sink.
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (s *SlackNotifier) Type() string {
+	return "slack"
+}
+
+// Send posts event to channel.Target in Slack's incoming-webhook format.
+func (s *SlackNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	body := map[string]string{
+		"text": fmt.Sprintf("*%s* [%s]\n%s", event.Title, event.Severity, event.Details),
+	}
+	return postJSON(ctx, channel.Target, body, nil)
+}
+
+// DiscordNotifier posts event to a Discord webhook URL.
+type DiscordNotifier struct{}
+
+// NewDiscordNotifier creates a Discord webhook sink.
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (d *DiscordNotifier) Type() string {
+	return "discord"
+}
+
+// Send posts event to channel.Target in Discord's webhook format.
+func (d *DiscordNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	body := map[string]string{
+		"content": fmt.Sprintf("**%s** [%s]\n%s", event.Title, event.Severity, event.Details),
+	}
+	return postJSON(ctx, channel.Target, body, nil)
+}
+
+// n8nAuthConfig is the JSON shape channel.Secret holds for an "n8n" channel,
+// describing how N8NNotifier should authenticate to the workflow webhook.
+type n8nAuthConfig struct {
+	// AuthType is "", "basic", or "header".
+	AuthType    string `json:"authType"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	HeaderName  string `json:"headerName"`
+	HeaderValue string `json:"headerValue"`
+}
+
+// N8NNotifier posts event as JSON to an n8n (or similarly Zapier-style)
+// workflow webhook URL, with optional basic-auth or a custom auth header
+// configured via channel.Secret.
+type N8NNotifier struct{}
+
+// NewN8NNotifier creates an n8n workflow-webhook sink.
+func NewN8NNotifier() *N8NNotifier {
+	return &N8NNotifier{}
+}
+
+// Type identifies this Notifier's NotificationChannel.ChannelType.
+func (w *N8NNotifier) Type() string {
+	return "n8n"
+}
+
+// Send posts event as JSON to channel.Target, attaching whatever auth
+// channel.Secret describes.
+func (w *N8NNotifier) Send(ctx context.Context, channel models.NotificationChannel, event NotificationEvent) error {
+	headers := map[string]string{}
+
+	if channel.Secret != "" {
+		var auth n8nAuthConfig
+		if err := json.Unmarshal([]byte(channel.Secret), &auth); err != nil {
+			return fmt.Errorf("invalid n8n auth config: %w", err)
+		}
+
+		switch auth.AuthType {
+		case "basic":
+			req, err := http.NewRequest(http.MethodPost, channel.Target, nil)
+			if err != nil {
+				return fmt.Errorf("failed to build request: %w", err)
+			}
+			req.SetBasicAuth(auth.Username, auth.Password)
+			headers["Authorization"] = req.Header.Get("Authorization")
+		case "header":
+			if auth.HeaderName != "" {
+				headers[auth.HeaderName] = auth.HeaderValue
+			}
+		}
+	}
+
+	return postJSON(ctx, channel.Target, event, headers)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"Wallet/backend/models"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reputationDecayLambda controls how quickly old evidence stops mattering.
+// At lambda=0.05/day a report from 60 days ago contributes ~5% of its
+// original weight; one from a week ago still contributes ~70%.
+const reputationDecayLambda = 0.05
+
+// severityWeights maps a Report.Severity rating (1-5) to the weight it
+// carries in the decayed reputation sum.
+var severityWeights = map[int]float64{
+	1: 0.05,
+	2: 0.15,
+	3: 0.30,
+	4: 0.55,
+	5: 0.85,
+}
+
+// ReputationService maintains a per-address reputation score derived from
+// confirmed scam reports, with older evidence fading via exponential decay:
+// score(t) = sum(w_i * exp(-lambda * (now - t_i))).
+type ReputationService struct {
+	db *gorm.DB
+}
+
+// NewReputationService creates a new reputation service instance.
+func NewReputationService(db *gorm.DB) *ReputationService {
+	return &ReputationService{db: db}
+}
+
+// GetReputation returns the last-computed reputation for address, computing
+// it for the first time if no row exists yet.
+func (s *ReputationService) GetReputation(address string) (*models.AddressReputation, error) {
+	var rep models.AddressReputation
+	err := s.db.Where("address = ?", address).First(&rep).Error
+	if err == nil {
+		return &rep, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to load reputation: %w", err)
+	}
+
+	return s.Recompute(address)
+}
+
+// Recompute recalculates address's reputation score from confirmed reports
+// and persists the result, creating the row if it doesn't exist yet.
+func (s *ReputationService) Recompute(address string) (*models.AddressReputation, error) {
+	var reports []models.Report
+	if err := s.db.Where("reported_address = ? AND status = ?", address, "verified").Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("failed to load reports for reputation: %w", err)
+	}
+
+	now := time.Now()
+	var score float64
+	for _, r := range reports {
+		weight, ok := severityWeights[r.Severity]
+		if !ok {
+			weight = severityWeights[1]
+		}
+		ageDays := now.Sub(r.CreatedAt).Hours() / 24
+		score += weight * math.Exp(-reputationDecayLambda*ageDays)
+	}
+
+	rep := models.AddressReputation{
+		Address:       address,
+		Score:         clamp01(score),
+		Confidence:    confidenceFromEvidence(len(reports)),
+		LastComputed:  now,
+		EvidenceCount: len(reports),
+	}
+
+	var existing models.AddressReputation
+	err := s.db.Where("address = ?", address).First(&existing).Error
+	switch err {
+	case nil:
+		rep.ID = existing.ID
+		if err := s.db.Save(&rep).Error; err != nil {
+			return nil, fmt.Errorf("failed to update reputation: %w", err)
+		}
+	case gorm.ErrRecordNotFound:
+		if err := s.db.Create(&rep).Error; err != nil {
+			return nil, fmt.Errorf("failed to create reputation: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to check existing reputation: %w", err)
+	}
+
+	return &rep, nil
+}
+
+// confidenceFromEvidence grows confidence asymptotically towards 1 as more
+// independent reports accumulate, so a single stale report never looks as
+// trustworthy as a dozen corroborating ones.
+func confidenceFromEvidence(evidenceCount int) float64 {
+	return clamp01(1 - math.Exp(-float64(evidenceCount)/5))
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/libp2p/go-libp2p/core/host"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// ReputationGossipTopic is the libp2p pubsub topic deployments use to share
+// signed reputation deltas with each other, similar to how decentralised
+// consensus systems gossip signed records between nodes.
+const ReputationGossipTopic = "wallet-firewall/blacklist/v1"
+
+// ReputationDelta is a single signed change to an address's reputation that
+// one deployment broadcasts to its peers.
+type ReputationDelta struct {
+	Address      string    `json:"address"`
+	ScoreDelta   float64   `json:"scoreDelta"`
+	Reason       string    `json:"reason"`
+	IssuedAt     time.Time `json:"issuedAt"`
+	OperatorAddr string    `json:"operatorAddress"` // secp256k1 address recovered from Signature
+	Signature    string    `json:"signature"`       // hex-encoded over the JSON of this struct with OperatorAddr and Signature both set to ""
+}
+
+// ReputationGossipBridge publishes and receives ReputationDelta messages
+// over a libp2p pubsub topic so multiple wallet-firewall deployments can
+// share blacklist intelligence without a central server.
+type ReputationGossipBridge struct {
+	reputationService *ReputationService
+	topic             *pubsub.Topic
+	subscription      *pubsub.Subscription
+	operatorKey       *ecdsa.PrivateKey
+	trustedOperators  map[string]bool
+}
+
+// NewReputationGossipBridge joins ReputationGossipTopic on the given libp2p
+// host and pubsub router. operatorKey signs deltas this node publishes;
+// trustedOperators is the allowlist of operator addresses whose deltas are
+// merged locally (an empty map means "trust no one": every received delta
+// is dropped, without being logged, until operators are explicitly trusted).
+func NewReputationGossipBridge(ctx context.Context, h host.Host, ps *pubsub.PubSub, reputationService *ReputationService, operatorKey *ecdsa.PrivateKey, trustedOperators map[string]bool) (*ReputationGossipBridge, error) {
+	topic, err := ps.Join(ReputationGossipTopic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join reputation gossip topic: %w", err)
+	}
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to reputation gossip topic: %w", err)
+	}
+
+	bridge := &ReputationGossipBridge{
+		reputationService: reputationService,
+		topic:             topic,
+		subscription:      sub,
+		operatorKey:       operatorKey,
+		trustedOperators:  trustedOperators,
+	}
+
+	go bridge.receiveLoop(ctx, h.ID().String())
+	return bridge, nil
+}
+
+// PublishDelta signs delta with this node's operator key and broadcasts it
+// to the topic.
+func (b *ReputationGossipBridge) PublishDelta(ctx context.Context, delta ReputationDelta) error {
+	delta.IssuedAt = time.Now()
+	delta.OperatorAddr = ""
+	delta.Signature = ""
+
+	payload, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reputation delta: %w", err)
+	}
+
+	digest := crypto.Keccak256(payload)
+	sig, err := crypto.Sign(digest, b.operatorKey)
+	if err != nil {
+		return fmt.Errorf("failed to sign reputation delta: %w", err)
+	}
+
+	delta.OperatorAddr = crypto.PubkeyToAddress(b.operatorKey.PublicKey).Hex()
+	delta.Signature = fmt.Sprintf("%x", sig)
+
+	signedPayload, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signed reputation delta: %w", err)
+	}
+
+	return b.topic.Publish(ctx, signedPayload)
+}
+
+// receiveLoop reads deltas from peers, verifies each against its claimed
+// OperatorAddr, and merges trusted ones into the local reputation store.
+func (b *ReputationGossipBridge) receiveLoop(ctx context.Context, selfPeerID string) {
+	for {
+		msg, err := b.subscription.Next(ctx)
+		if err != nil {
+			// Context cancelled, or the subscription was torn down.
+			return
+		}
+		if msg.ReceivedFrom.String() == selfPeerID {
+			continue
+		}
+
+		var delta ReputationDelta
+		if err := json.Unmarshal(msg.Data, &delta); err != nil {
+			continue
+		}
+
+		if err := b.verifyAndMerge(delta); err != nil {
+			continue
+		}
+	}
+}
+
+// verifyAndMerge checks the delta's signature recovers to its claimed
+// OperatorAddr, that OperatorAddr is trusted, and then triggers a local
+// Recompute for the address. A trusted peer's delta is treated as a signal
+// to re-check an address rather than applied as a raw score adjustment,
+// since this node only trusts evidence (Reports) it has itself verified;
+// storing remote evidence directly is left for a future iteration.
+func (b *ReputationGossipBridge) verifyAndMerge(delta ReputationDelta) error {
+	claimedOperatorAddr := delta.OperatorAddr
+	if !b.trustedOperators[claimedOperatorAddr] {
+		return fmt.Errorf("untrusted operator: %s", claimedOperatorAddr)
+	}
+
+	claimedSig := delta.Signature
+	// Zero the same fields PublishDelta zeroed before signing, so this side
+	// recomputes the identical digest instead of one that also commits to
+	// OperatorAddr/Signature (which aren't known until after signing).
+	delta.OperatorAddr = ""
+	delta.Signature = ""
+	payload, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delta for verification: %w", err)
+	}
+
+	var sig []byte
+	if _, err := fmt.Sscanf(claimedSig, "%x", &sig); err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	digest := crypto.Keccak256(payload)
+	pubKey, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return fmt.Errorf("failed to recover operator key: %w", err)
+	}
+	if crypto.PubkeyToAddress(*pubKey).Hex() != claimedOperatorAddr {
+		return fmt.Errorf("signature does not match claimed operator address")
+	}
+
+	_, err = b.reputationService.Recompute(delta.Address)
+	return err
+}
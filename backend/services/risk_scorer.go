@@ -0,0 +1,106 @@
+package services
+
+import "Wallet/backend/models"
+
+// featureVectorVersion is bumped whenever the layout of the feature vector
+// returned by FeatureExtractor.Extract changes, so stored/replayed feature
+// vectors can be told apart from vectors produced by a different version.
+const featureVectorVersion = 1
+
+// featureNames is the stable, ordered list of features FeatureExtractor
+// produces. Index i of a []float64 returned by Extract always corresponds to
+// featureNames[i].
+var featureNames = []string{
+	"tx_value",
+	"tx_velocity_per_hour",
+	"counterparty_entropy",
+	"gas_price_zscore",
+	"contract_eoa_ratio",
+	"to_address_first_seen_age_days",
+	"value_to_30d_avg_outflow_ratio",
+	"destination_scam_history",
+}
+
+// FeatureContribution describes how much a single feature pushed a risk
+// score up or down, used to render SHAP-style explanations.
+type FeatureContribution struct {
+	Feature      string  `json:"feature"`
+	Value        float64 `json:"value"`
+	Contribution float64 `json:"contribution"`
+}
+
+// RiskAssessment is the full output of a RiskScorer, replacing the bare
+// float64 risk score the external ML API used to return.
+type RiskAssessment struct {
+	Score        float64               `json:"score"`
+	Confidence   float64               `json:"confidence"`
+	TopFeatures  []FeatureContribution `json:"topFeatures"`
+	ModelVersion string                `json:"modelVersion"`
+}
+
+// RiskScorer produces a RiskAssessment from a transaction's feature vector.
+// AIService is built around this interface rather than a single hardcoded
+// model so operators can swap in a different scorer without touching
+// handler code.
+type RiskScorer interface {
+	Score(features []float64) (RiskAssessment, error)
+	// Name identifies the scorer implementation, surfaced in ModelVersion.
+	Name() string
+}
+
+// FeatureExtractor materialises a stable, versioned []float64 from a
+// transaction plus whatever historical context WalletAnalyticsService can
+// provide. Features it cannot compute (because analyticsService is nil, or
+// the lookup fails) are left at zero rather than causing the request to
+// fail, matching how AnalyzeTransactionEnhanced already degrades gracefully.
+type FeatureExtractor struct {
+	analyticsService *WalletAnalyticsService
+}
+
+// NewFeatureExtractor creates a FeatureExtractor backed by the given
+// analytics service. analyticsService may be nil, in which case every
+// history-derived feature is left at zero.
+func NewFeatureExtractor(analyticsService *WalletAnalyticsService) *FeatureExtractor {
+	return &FeatureExtractor{analyticsService: analyticsService}
+}
+
+// Extract builds the feature vector for tx. The returned slice always has
+// len(featureNames) elements, in the order described by featureNames.
+func (fe *FeatureExtractor) Extract(tx models.Transaction) []float64 {
+	features := make([]float64, len(featureNames))
+	features[0] = tx.Value
+
+	if fe.analyticsService == nil {
+		return features
+	}
+
+	if velocity, err := fe.analyticsService.GetTransactionVelocity(tx.FromAddress); err == nil {
+		features[1] = velocity
+	}
+	if entropy, err := fe.analyticsService.GetCounterpartyEntropy(tx.FromAddress); err == nil {
+		features[2] = entropy
+	}
+	if zscore, err := fe.analyticsService.GetGasPriceZScore(tx.FromAddress); err == nil {
+		features[3] = zscore
+	}
+	if ratio, err := fe.analyticsService.GetContractEOARatio(tx.FromAddress); err == nil {
+		features[4] = ratio
+	}
+	if ageDays, err := fe.analyticsService.GetAddressFirstSeenAgeDays(tx.ToAddress); err == nil {
+		features[5] = ageDays
+	}
+	if ratio, err := fe.analyticsService.GetValueTo30DayAvgOutflowRatio(tx.FromAddress, tx.Value); err == nil {
+		features[6] = ratio
+	}
+	if scamHistory, err := fe.analyticsService.GetAddressScamHistory(tx.ToAddress); err == nil {
+		features[7] = float64(scamHistory.ScamCount)
+	}
+
+	return features
+}
+
+// FeatureNames returns the ordered feature names corresponding to Extract's
+// output, so callers (e.g. GetRiskExplanation) can label contributions.
+func FeatureNames() []string {
+	return featureNames
+}
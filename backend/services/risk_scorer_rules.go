@@ -0,0 +1,95 @@
+package services
+
+import "sort"
+
+// ruleWeights assigns a contribution weight to each feature produced by
+// FeatureExtractor. They were picked to roughly mirror the risk bands the
+// old hardcoded "Fraud"/"Suspicious"/"Safe" labels mapped to (0.85/0.5/0.1),
+// and can be retuned without touching callers since they're only consumed
+// through the RiskScorer interface.
+var ruleWeights = []float64{
+	0,    // tx_value: scaled dynamically, see RulesRiskScorer.Score
+	0.15, // tx_velocity_per_hour
+	0.10, // counterparty_entropy (low entropy -> high risk, so this is inverted below)
+	0.15, // gas_price_zscore
+	0.05, // contract_eoa_ratio
+	0.10, // to_address_first_seen_age_days (inverted: newer address -> higher risk)
+	0.20, // value_to_30d_avg_outflow_ratio
+	0.45, // destination_scam_history
+}
+
+// RulesRiskScorer is a local, dependency-free RiskScorer that computes a
+// weighted sum over the feature vector. It requires no network call and no
+// model file, making it the default scorer and the fallback when an
+// ONNXRiskScorer fails to load.
+type RulesRiskScorer struct{}
+
+// NewRulesRiskScorer creates a heuristic RiskScorer.
+func NewRulesRiskScorer() *RulesRiskScorer {
+	return &RulesRiskScorer{}
+}
+
+// Name implements RiskScorer.
+func (r *RulesRiskScorer) Name() string {
+	return "rules-v1"
+}
+
+// Score implements RiskScorer using a weighted, capped sum of feature
+// contributions. Two features are inverted before weighting because a
+// *low* value is the risky signal (fresh counterparties, narrow entropy).
+func (r *RulesRiskScorer) Score(features []float64) (RiskAssessment, error) {
+	contributions := make([]FeatureContribution, 0, len(featureNames))
+	var score float64
+
+	for i, name := range featureNames {
+		if i >= len(features) {
+			break
+		}
+		value := features[i]
+
+		var contribution float64
+		switch i {
+		case 0: // tx_value doesn't have a fixed weight; large values raise risk slightly
+			contribution = clamp01(value/1_000_000) * 0.10
+		case 2: // counterparty_entropy: low diversity of counterparties is risky
+			contribution = clamp01(1-value) * ruleWeights[i]
+		case 5: // to_address_first_seen_age_days: brand-new addresses are risky
+			contribution = clamp01(1 - value/30) * ruleWeights[i]
+		default:
+			contribution = clamp01(value) * ruleWeights[i]
+		}
+
+		score += contribution
+		contributions = append(contributions, FeatureContribution{
+			Feature:      name,
+			Value:        value,
+			Contribution: contribution,
+		})
+	}
+
+	sort.Slice(contributions, func(i, j int) bool {
+		return contributions[i].Contribution > contributions[j].Contribution
+	})
+
+	top := contributions
+	if len(top) > 3 {
+		top = top[:3]
+	}
+
+	return RiskAssessment{
+		Score:        clamp01(score),
+		Confidence:   0.6, // heuristic scorer is less confident than a trained model
+		TopFeatures:  top,
+		ModelVersion: r.Name(),
+	}, nil
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
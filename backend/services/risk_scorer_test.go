@@ -0,0 +1,99 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"Wallet/backend/models"
+)
+
+// floatsClose reports whether a and b differ by no more than 1e-9, the
+// tolerance these golden vectors need since a few RulesRiskScorer
+// contributions (e.g. the to_address_first_seen_age_days term, which divides
+// by 30) aren't exactly representable in float64.
+func floatsClose(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestFeatureExtractorExtract_NilAnalyticsService pins Extract's documented
+// degrade-gracefully behavior: with no analyticsService, every
+// history-derived feature stays at zero and only tx_value (index 0) is
+// populated.
+func TestFeatureExtractorExtract_NilAnalyticsService(t *testing.T) {
+	fe := NewFeatureExtractor(nil)
+	tx := models.Transaction{FromAddress: "0xFrom", ToAddress: "0xTo", Value: 1.5}
+
+	got := fe.Extract(tx)
+	want := []float64{1.5, 0, 0, 0, 0, 0, 0, 0}
+
+	if len(got) != len(want) {
+		t.Fatalf("Extract returned %d features, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !floatsClose(got[i], want[i]) {
+			t.Errorf("feature %d (%s): got %v, want %v", i, featureNames[i], got[i], want[i])
+		}
+	}
+}
+
+// TestRulesRiskScorer_Score runs RulesRiskScorer.Score against golden
+// feature vectors with hand-computed expected scores, so a change to the
+// weighting/inversion logic in risk_scorer_rules.go has to be a deliberate,
+// visible change to this test rather than an unnoticed regression.
+func TestRulesRiskScorer_Score(t *testing.T) {
+	tests := []struct {
+		name            string
+		features        []float64
+		wantScore       float64
+		wantConfidence  float64
+		wantTopFeatures []string
+	}{
+		{
+			// counterparty_entropy and to_address_first_seen_age_days both
+			// land on an exact 0.10 contribution here, so which of the two
+			// sorts first is unspecified (sort.Slice isn't stable) -
+			// TopFeatures ordering isn't asserted for this case.
+			name:           "all zero features score low via the two inverted terms",
+			features:       []float64{0, 0, 0, 0, 0, 0, 0, 0},
+			wantScore:      0.20,
+			wantConfidence: 0.6,
+		},
+		{
+			name:            "mixed feature vector",
+			features:        []float64{200000, 0.6, 0.9, 0.4, 0.5, 25, 0.35, 0.8},
+			wantScore:       0.651666666666667,
+			wantConfidence:  0.6,
+			wantTopFeatures: []string{"destination_scam_history", "tx_velocity_per_hour", "value_to_30d_avg_outflow_ratio"},
+		},
+	}
+
+	scorer := NewRulesRiskScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assessment, err := scorer.Score(tt.features)
+			if err != nil {
+				t.Fatalf("Score returned error: %v", err)
+			}
+			if !floatsClose(assessment.Score, tt.wantScore) {
+				t.Errorf("Score = %v, want %v", assessment.Score, tt.wantScore)
+			}
+			if !floatsClose(assessment.Confidence, tt.wantConfidence) {
+				t.Errorf("Confidence = %v, want %v", assessment.Confidence, tt.wantConfidence)
+			}
+			if assessment.ModelVersion != scorer.Name() {
+				t.Errorf("ModelVersion = %q, want %q", assessment.ModelVersion, scorer.Name())
+			}
+
+			if len(tt.wantTopFeatures) > 0 {
+				if len(assessment.TopFeatures) < len(tt.wantTopFeatures) {
+					t.Fatalf("TopFeatures has %d entries, want at least %d", len(assessment.TopFeatures), len(tt.wantTopFeatures))
+				}
+				for i, want := range tt.wantTopFeatures {
+					if assessment.TopFeatures[i].Feature != want {
+						t.Errorf("TopFeatures[%d] = %q, want %q", i, assessment.TopFeatures[i].Feature, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RuleContext supplies variable values by name to EvaluateRuleExpression.
+// SampleRuleContext documents the canonical variable set FirewallHandler
+// populates.
+type RuleContext map[string]interface{}
+
+// SampleRuleContext returns a zero-valued RuleContext covering every
+// variable FirewallHandler's rule evaluation populates, used to validate a
+// rule's Expression at creation/update time: referencing anything outside
+// this set is rejected as an unknown variable rather than silently never
+// matching at evaluation time.
+func SampleRuleContext() RuleContext {
+	return RuleContext{
+		"risk":         0.0,
+		"value":        0.0,
+		"velocity":     0.0,
+		"report_count": 0.0,
+		"to_address":   "",
+		"from_address": "",
+		"watchlist":    []string{},
+		"transaction_id": uint(0),
+	}
+}
+
+// EvaluateRuleExpression parses and evaluates a small boolean DSL rule
+// expression against ctx, returning whether it matched. The grammar supports
+// AND/OR/NOT, parentheses, comparisons (> >= < <= == !=), "in" against a
+// []string variable, and operands that are identifiers, numbers, strings, or
+// true/false, e.g.:
+//
+//	risk > 0.5 AND to_address in watchlist
+//	value > 1000 OR (velocity > 5 AND report_count > 0)
+func EvaluateRuleExpression(expression string, ctx RuleContext) (bool, error) {
+	tokens, err := tokenizeRuleExpression(expression)
+	if err != nil {
+		return false, err
+	}
+
+	p := &ruleParser{tokens: tokens, ctx: ctx}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if !p.atEnd() {
+		return false, fmt.Errorf("unexpected token %q", p.peek().value)
+	}
+
+	matched, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("rule expression did not evaluate to a boolean")
+	}
+	return matched, nil
+}
+
+type ruleToken struct {
+	kind  string // "ident", "num", "str", "op", "lparen", "rparen"
+	value string
+}
+
+func tokenizeRuleExpression(expr string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, ruleToken{"lparen", "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, ruleToken{"rparen", ")"})
+			i++
+		case c == '\'' || c == '"':
+			quote := c
+			j := i + 1
+			for j < len(expr) && expr[j] != quote {
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, ruleToken{"str", expr[i+1 : j]})
+			i = j + 1
+		case c == '>' || c == '<' || c == '=' || c == '!':
+			op := string(c)
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				op += "="
+				i += 2
+			} else {
+				i++
+			}
+			tokens = append(tokens, ruleToken{"op", op})
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, ruleToken{"num", expr[i:j]})
+			i = j
+		case isRuleIdentChar(c):
+			j := i
+			for j < len(expr) && isRuleIdentChar(expr[j]) {
+				j++
+			}
+			word := expr[i:j]
+			switch strings.ToUpper(word) {
+			case "AND", "OR", "NOT", "IN", "TRUE", "FALSE":
+				tokens = append(tokens, ruleToken{"op", strings.ToUpper(word)})
+			default:
+				tokens = append(tokens, ruleToken{"ident", word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q in rule expression", string(c))
+		}
+	}
+	return tokens, nil
+}
+
+func isRuleIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ruleParser is a small recursive-descent parser/evaluator combined into a
+// single pass, since rule expressions are tiny and only ever evaluated once
+// per event — there's no reuse to justify building a separate AST.
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+	ctx    RuleContext
+}
+
+func (p *ruleParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *ruleParser) peek() ruleToken {
+	if p.atEnd() {
+		return ruleToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *ruleParser) next() ruleToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *ruleParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == "op" && p.peek().value == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lb, rb, err := asBools(left, right)
+		if err != nil {
+			return nil, err
+		}
+		left = lb || rb
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (interface{}, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for !p.atEnd() && p.peek().kind == "op" && p.peek().value == "AND" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		lb, rb, err := asBools(left, right)
+		if err != nil {
+			return nil, err
+		}
+		left = lb && rb
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseNot() (interface{}, error) {
+	if !p.atEnd() && p.peek().kind == "op" && p.peek().value == "NOT" {
+		p.next()
+		val, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		b, ok := val.(bool)
+		if !ok {
+			return nil, fmt.Errorf("NOT requires a boolean operand")
+		}
+		return !b, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *ruleParser) parseComparison() (interface{}, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.atEnd() {
+		return left, nil
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == "op" && isComparisonOp(tok.value):
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return compareRuleValues(tok.value, left, right)
+	case tok.kind == "op" && tok.value == "IN":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return memberOfRuleList(left, right)
+	default:
+		return left, nil
+	}
+}
+
+func isComparisonOp(op string) bool {
+	switch op {
+	case ">", "<", ">=", "<=", "==", "!=":
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) parsePrimary() (interface{}, error) {
+	if p.atEnd() {
+		return nil, fmt.Errorf("unexpected end of rule expression")
+	}
+	tok := p.next()
+	switch tok.kind {
+	case "lparen":
+		val, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.atEnd() || p.peek().kind != "rparen" {
+			return nil, fmt.Errorf("expected closing parenthesis")
+		}
+		p.next()
+		return val, nil
+	case "num":
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.value)
+		}
+		return f, nil
+	case "str":
+		return tok.value, nil
+	case "op":
+		switch tok.value {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		}
+		return nil, fmt.Errorf("unexpected token %q", tok.value)
+	case "ident":
+		val, ok := p.ctx[tok.value]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %q in rule expression", tok.value)
+		}
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unexpected token %q", tok.value)
+	}
+}
+
+func asBools(a, b interface{}) (bool, bool, error) {
+	ab, ok := a.(bool)
+	if !ok {
+		return false, false, fmt.Errorf("expected boolean operand, got %v", a)
+	}
+	bb, ok := b.(bool)
+	if !ok {
+		return false, false, fmt.Errorf("expected boolean operand, got %v", b)
+	}
+	return ab, bb, nil
+}
+
+func compareRuleValues(op string, left, right interface{}) (bool, error) {
+	if lf, lok := toRuleFloat(left); lok {
+		if rf, rok := toRuleFloat(right); rok {
+			switch op {
+			case ">":
+				return lf > rf, nil
+			case "<":
+				return lf < rf, nil
+			case ">=":
+				return lf >= rf, nil
+			case "<=":
+				return lf <= rf, nil
+			case "==":
+				return lf == rf, nil
+			case "!=":
+				return lf != rf, nil
+			}
+		}
+	}
+
+	if ls, lok := left.(string); lok {
+		if rs, rok := right.(string); rok {
+			switch op {
+			case "==":
+				return ls == rs, nil
+			case "!=":
+				return ls != rs, nil
+			default:
+				return false, fmt.Errorf("operator %q is not valid between strings", op)
+			}
+		}
+	}
+
+	return false, fmt.Errorf("cannot compare %v and %v with %q", left, right, op)
+}
+
+func memberOfRuleList(value, list interface{}) (bool, error) {
+	s, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("left side of IN must be a string")
+	}
+	items, ok := list.([]string)
+	if !ok {
+		return false, fmt.Errorf("right side of IN must be a list")
+	}
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toRuleFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	}
+	return 0, false
+}
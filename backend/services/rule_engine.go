@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"Wallet/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// RuleEngine evaluates a wallet's enabled Rule rows against every
+// TransactionAnalyzed event on an EventBus, running the declarative
+// notify/auto_block actions a matching rule specifies. It complements
+// rather than replaces FirewallHandler's baseline AI-risk classification:
+// that still sets a transaction's initial status, and rules layer
+// per-wallet signals (watchlists, velocity, report history) on top,
+// letting a rule auto_block a transaction the baseline risk score alone
+// wouldn't have flagged.
+//
+// Rules are read fresh from the database on every event rather than cached,
+// so edits made via the /api/rules endpoints are hot-reloaded with no
+// restart required.
+type RuleEngine struct {
+	db                  *gorm.DB
+	notificationService *NotificationService
+}
+
+// NewRuleEngine creates a RuleEngine and subscribes it to
+// EventTransactionAnalyzed on bus.
+func NewRuleEngine(db *gorm.DB, bus *EventBus, notificationService *NotificationService) *RuleEngine {
+	engine := &RuleEngine{db: db, notificationService: notificationService}
+	bus.Subscribe(EventTransactionAnalyzed, engine.handleTransactionAnalyzed)
+	return engine
+}
+
+func (re *RuleEngine) handleTransactionAnalyzed(event Event) {
+	var rules []models.Rule
+	if err := re.db.Where("wallet_address = ? AND enabled = ?", event.WalletAddress, true).Find(&rules).Error; err != nil {
+		log.Printf("RuleEngine: failed to load rules for %s: %v", event.WalletAddress, err)
+		return
+	}
+
+	ctx := RuleContext(event.Payload)
+	for _, rule := range rules {
+		matched, err := EvaluateRuleExpression(rule.Expression, ctx)
+		if err != nil {
+			log.Printf("RuleEngine: rule %d (%s) failed to evaluate: %v", rule.ID, rule.Name, err)
+			continue
+		}
+		if matched {
+			re.runActions(rule, event)
+		}
+	}
+}
+
+func (re *RuleEngine) runActions(rule models.Rule, event Event) {
+	var actions []string
+	if err := json.Unmarshal([]byte(rule.Actions), &actions); err != nil {
+		log.Printf("RuleEngine: rule %d (%s) has invalid actions: %v", rule.ID, rule.Name, err)
+		return
+	}
+
+	for _, action := range actions {
+		switch {
+		case action == "auto_block":
+			re.autoBlock(rule, event)
+		case strings.HasPrefix(action, "notify:"):
+			re.notify(rule, event)
+		default:
+			log.Printf("RuleEngine: rule %d (%s) has unknown action %q", rule.ID, rule.Name, action)
+		}
+	}
+}
+
+func (re *RuleEngine) autoBlock(rule models.Rule, event Event) {
+	txID, ok := event.Payload["transaction_id"].(uint)
+	if !ok {
+		log.Printf("RuleEngine: rule %d (%s) matched auto_block but event carried no transaction_id", rule.ID, rule.Name)
+		return
+	}
+	if err := re.db.Model(&models.Transaction{}).Where("id = ?", txID).Update("status", "blocked").Error; err != nil {
+		log.Printf("RuleEngine: rule %d (%s) failed to auto-block transaction %d: %v", rule.ID, rule.Name, txID, err)
+	}
+}
+
+// notify dispatches a "rule_match" event through the shared
+// NotificationService, which fans it out to every channel the wallet has
+// registered. The specific channel names in the rule's "notify:X" actions
+// are descriptive for the rule's author; delivery is still governed by
+// which channels the wallet actually registered and their MinSeverity.
+func (re *RuleEngine) notify(rule models.Rule, event Event) {
+	if re.notificationService == nil {
+		return
+	}
+
+	details, _ := json.Marshal(event.Payload)
+	re.notificationService.Dispatch(context.Background(), event.WalletAddress, NotificationEvent{
+		Type:      "rule_match",
+		Severity:  "medium",
+		Title:     "Rule matched: " + rule.Name,
+		Details:   string(details),
+		Timestamp: time.Now(),
+	})
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -20,12 +24,53 @@ type TelegramService struct {
 	Token   string
 	BaseURL string
 	DB      *gorm.DB // Database connection for storing mappings
+
+	// ipAllowlistEnabled gates GetWebhookHandler on the request's source IP
+	// falling within telegramCIDRRanges, in addition to the secret_token
+	// check. Off by default since it breaks behind most reverse proxies that
+	// don't forward the true client IP; see SetWebhookIPAllowlist.
+	ipAllowlistEnabled bool
+}
+
+// SetWebhookIPAllowlist enables or disables rejecting webhook requests whose
+// source IP falls outside Telegram's published CIDR ranges.
+func (ts *TelegramService) SetWebhookIPAllowlist(enabled bool) {
+	ts.ipAllowlistEnabled = enabled
+}
+
+// telegramCIDRRanges are Telegram's published webhook source ranges.
+// See https://core.telegram.org/bots/webhooks#the-short-version.
+var telegramCIDRRanges = []string{
+	"149.154.160.0/20",
+	"91.108.4.0/22",
 }
 
 // TelegramUpdate represents an update from Telegram
 type TelegramUpdate struct {
-	UpdateID int              `json:"update_id"`
-	Message  *TelegramMessage `json:"message,omitempty"`
+	UpdateID      int                    `json:"update_id"`
+	Message       *TelegramMessage       `json:"message,omitempty"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query,omitempty"`
+}
+
+// TelegramCallbackQuery represents a press of an inline-keyboard button.
+type TelegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    *TelegramUser    `json:"from"`
+	Message *TelegramMessage `json:"message"`
+	Data    string           `json:"data"`
+}
+
+// InlineKeyboardButton is one button of an InlineKeyboardMarkup. CallbackData
+// is what Telegram echoes back in TelegramCallbackQuery.Data when pressed.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// InlineKeyboardMarkup is Telegram's reply_markup shape for inline keyboards,
+// a grid of button rows shown under a message.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
 }
 
 // TelegramMessage represents a message in a Telegram update
@@ -71,6 +116,12 @@ func NewTelegramService(token string, db *gorm.DB) *TelegramService {
 
 // SendMessage sends a message to a specific Telegram chat
 func (ts *TelegramService) SendMessage(chatID int64, text string) error {
+	return ts.SendMessageWithKeyboard(chatID, text, nil)
+}
+
+// SendMessageWithKeyboard sends a message to a specific Telegram chat,
+// optionally attaching an inline keyboard as reply_markup.
+func (ts *TelegramService) SendMessageWithKeyboard(chatID int64, text string, keyboard *InlineKeyboardMarkup) error {
 	if ts.Token == "" {
 		return fmt.Errorf("telegram token not configured")
 	}
@@ -81,6 +132,9 @@ func (ts *TelegramService) SendMessage(chatID int64, text string) error {
 		"text":       text,
 		"parse_mode": "HTML",
 	}
+	if keyboard != nil {
+		payload["reply_markup"] = keyboard
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -100,6 +154,80 @@ func (ts *TelegramService) SendMessage(chatID int64, text string) error {
 	return nil
 }
 
+// answerCallbackQuery acknowledges an inline-keyboard button press, showing
+// text as a brief toast in the Telegram client.
+func (ts *TelegramService) answerCallbackQuery(callbackQueryID, text string) error {
+	if ts.Token == "" {
+		return fmt.Errorf("telegram token not configured")
+	}
+
+	url := fmt.Sprintf("%s/answerCallbackQuery", ts.BaseURL)
+	payload := map[string]interface{}{
+		"callback_query_id": callbackQueryID,
+		"text":              text,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram payload: %w", err)
+	}
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(jsonPayload)))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram API returned non-OK status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// editMessageText rewrites a previously-sent message, used to remove a
+// pending action's inline keyboard once it's been acted on.
+func (ts *TelegramService) editMessageText(chatID int64, messageID int, text string) error {
+	if ts.Token == "" {
+		return fmt.Errorf("telegram token not configured")
+	}
+
+	url := fmt.Sprintf("%s/editMessageText", ts.BaseURL)
+	payload := map[string]interface{}{
+		"chat_id":    chatID,
+		"message_id": messageID,
+		"text":       text,
+		"parse_mode": "HTML",
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram payload: %w", err)
+	}
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(jsonPayload)))
+	if err != nil {
+		return fmt.Errorf("failed to edit telegram message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram API returned non-OK status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// generateActionToken returns a short random hex token suitable for a
+// PendingAction's callback_data; Telegram caps callback_data at 64 bytes.
+func generateActionToken() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate action token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 // NotifySecurityAlert sends a security alert to the user's telegram if they have linked their account
 func (ts *TelegramService) NotifySecurityAlert(walletAddress string, alert *models.SecurityAlert) error {
 	var mapping models.TelegramMapping
@@ -128,6 +256,75 @@ func (ts *TelegramService) NotifySecurityAlert(walletAddress string, alert *mode
 	return ts.SendMessage(chatIDInt, message)
 }
 
+// pendingActionTTL bounds how long an inline-keyboard button from
+// NotifyTransactionAlert stays valid before handleCallbackQuery rejects it.
+const pendingActionTTL = 24 * time.Hour
+
+// NotifyTransactionAlert sends a security alert for a specific transaction,
+// like NotifySecurityAlert, but attaches inline buttons ("Allow once",
+// "Block & report", "Always allow sender") so the user can act without
+// leaving Telegram. Each button's callback_data is a token recorded in a
+// PendingAction row that handleCallbackQuery resolves back to the
+// transaction, wallet, and counterparty address.
+func (ts *TelegramService) NotifyTransactionAlert(walletAddress string, transactionID uint, counterpartyAddress string, alert *models.SecurityAlert) error {
+	var mapping models.TelegramMapping
+	result := ts.DB.Where("wallet_address = ? AND is_active = ?", walletAddress, true).First(&mapping)
+	if result.Error != nil {
+		return fmt.Errorf("no telegram chat linked to wallet %s: %w", walletAddress, result.Error)
+	}
+
+	chatIDInt, err := strconv.ParseInt(mapping.ChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID format: %w", err)
+	}
+
+	actions := []struct {
+		label  string
+		action string
+	}{
+		{"✅ Allow once", "allow_once"},
+		{"🚫 Block & report", "block_and_report"},
+		{"⭐ Always allow sender", "always_allow_sender"},
+	}
+
+	row := make([]InlineKeyboardButton, 0, len(actions))
+	for _, a := range actions {
+		token, err := generateActionToken()
+		if err != nil {
+			return err
+		}
+
+		pending := models.PendingAction{
+			Token:         token,
+			ChatID:        mapping.ChatID,
+			Action:        a.action,
+			TransactionID: transactionID,
+			WalletAddress: walletAddress,
+			CounterParty:  counterpartyAddress,
+			ExpiresAt:     time.Now().Add(pendingActionTTL),
+		}
+		if err := ts.DB.Create(&pending).Error; err != nil {
+			return fmt.Errorf("failed to record pending action: %w", err)
+		}
+
+		row = append(row, InlineKeyboardButton{Text: a.label, CallbackData: token})
+	}
+
+	message := fmt.Sprintf("⚠️ <b>SECURITY ALERT</b> ⚠️\n\n"+
+		"<b>Type:</b> %s\n"+
+		"<b>Severity:</b> %s\n"+
+		"<b>Details:</b> %s\n\n"+
+		"<b>Time:</b> %s",
+		alert.Type,
+		alert.Severity,
+		alert.Details,
+		time.Unix(alert.Timestamp, 0).Format(time.RFC1123),
+	)
+
+	keyboard := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{row}}
+	return ts.SendMessageWithKeyboard(chatIDInt, message, keyboard)
+}
+
 // NotifyScamReport sends a notification when a scam has been reported
 func (ts *TelegramService) NotifyScamReport(walletAddress string, report *models.Report) error {
 	var mapping models.TelegramMapping
@@ -184,26 +381,207 @@ func (ts *TelegramService) LinkWallet(chatID string, walletAddress string, userN
 	return ts.DB.Create(&mapping).Error
 }
 
+// LinkWalletToTelegramUser associates a wallet address with a Telegram user
+// ID that has already been verified by VerifyTelegramLoginWidget or
+// VerifyTelegramMiniApp. Unlike LinkWallet (driven by the unauthenticated
+// /link chat command), the caller here has cryptographic proof the wallet
+// owner controls telegramUserID.
+func (ts *TelegramService) LinkWalletToTelegramUser(walletAddress string, telegramUserID int64) error {
+	var existingMapping models.TelegramMapping
+	result := ts.DB.Where("wallet_address = ?", walletAddress).First(&existingMapping)
+
+	if result.Error == nil {
+		existingMapping.TelegramUserID = telegramUserID
+		existingMapping.IsActive = true
+		return ts.DB.Save(&existingMapping).Error
+	}
+
+	mapping := models.TelegramMapping{
+		WalletAddress:  walletAddress,
+		TelegramUserID: telegramUserID,
+		IsActive:       true,
+	}
+	return ts.DB.Create(&mapping).Error
+}
+
 // GetWebhookHandler returns a Gin handler for Telegram webhooks
 func (ts *TelegramService) GetWebhookHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if err := ts.verifyWebhookRequest(c); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
 		var update TelegramUpdate
 		if err := c.ShouldBindJSON(&update); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 			return
 		}
 
+		// Telegram delivers at-least-once, so a retried update must not be
+		// reprocessed (e.g. re-sending an inline keyboard, re-filing a report).
+		if !ts.markUpdateProcessed(update.UpdateID) {
+			c.Status(http.StatusOK)
+			return
+		}
+
 		// Process update
 		if update.Message != nil {
 			ts.processMessage(update.Message)
 		}
+		if update.CallbackQuery != nil {
+			ts.handleCallbackQuery(update.CallbackQuery)
+		}
 
 		c.Status(http.StatusOK)
 	}
 }
 
+// verifyWebhookRequest rejects any webhook request that doesn't carry the
+// secret_token registered by SetWebhook/RotateWebhookSecret in the
+// X-Telegram-Bot-Api-Secret-Token header, compared in constant time, plus
+// an optional source-IP check against telegramCIDRRanges.
+func (ts *TelegramService) verifyWebhookRequest(c *gin.Context) error {
+	if ts.ipAllowlistEnabled && !isTelegramIP(c.ClientIP()) {
+		return fmt.Errorf("request did not originate from a Telegram IP range")
+	}
+
+	var row models.Config
+	if err := ts.DB.Where("key = ?", webhookSecretConfigKey).First(&row).Error; err != nil {
+		return fmt.Errorf("webhook secret not configured")
+	}
+
+	header := c.GetHeader("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(header), []byte(row.Value)) != 1 {
+		return fmt.Errorf("invalid webhook secret token")
+	}
+	return nil
+}
+
+// isTelegramIP reports whether ip falls within one of telegramCIDRRanges.
+func isTelegramIP(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, cidr := range telegramCIDRRanges {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// markUpdateProcessed records updateID as handled, returning false if it was
+// already recorded (a retried at-least-once delivery). It also
+// opportunistically prunes entries older than processedUpdateTTL so the
+// table doesn't grow unbounded.
+func (ts *TelegramService) markUpdateProcessed(updateID int) bool {
+	if err := ts.DB.Create(&models.ProcessedTelegramUpdate{UpdateID: int64(updateID)}).Error; err != nil {
+		return false
+	}
+	ts.DB.Where("created_at < ?", time.Now().Add(-processedUpdateTTL)).Delete(&models.ProcessedTelegramUpdate{})
+	return true
+}
+
+// handleCallbackQuery resolves an inline-keyboard button press back to its
+// PendingAction, verifies the pressing user owns the linked wallet, performs
+// the action, and acknowledges it via answerCallbackQuery + editMessageText.
+func (ts *TelegramService) handleCallbackQuery(callback *TelegramCallbackQuery) {
+	if callback.From == nil || callback.Message == nil || callback.Message.Chat == nil {
+		return
+	}
+
+	var pending models.PendingAction
+	if err := ts.DB.Where("token = ?", callback.Data).First(&pending).Error; err != nil {
+		ts.answerCallbackQuery(callback.ID, "This action is no longer available.")
+		return
+	}
+	if pending.Used || time.Now().After(pending.ExpiresAt) {
+		ts.answerCallbackQuery(callback.ID, "This action has expired.")
+		return
+	}
+
+	chatID := fmt.Sprintf("%d", callback.Message.Chat.ID)
+	if chatID != pending.ChatID {
+		ts.answerCallbackQuery(callback.ID, "This action isn't for you.")
+		return
+	}
+
+	// The callback's chat must still be an active link to the wallet the
+	// action was issued for, and the pressing user (callback.From.ID) must be
+	// the Telegram user bound to that link, so a button can't be honored
+	// after unlinking or by another member of the chat.
+	var mapping models.TelegramMapping
+	if err := ts.DB.Where("chat_id = ? AND telegram_user_id = ? AND wallet_address = ? AND is_active = ?", chatID, callback.From.ID, pending.WalletAddress, true).First(&mapping).Error; err != nil {
+		ts.answerCallbackQuery(callback.ID, "Your Telegram account isn't linked to this wallet anymore.")
+		return
+	}
+
+	var resultText string
+	switch pending.Action {
+	case "allow_once":
+		if err := ts.DB.Model(&models.Transaction{}).Where("id = ?", pending.TransactionID).Update("status", "safe").Error; err != nil {
+			ts.answerCallbackQuery(callback.ID, "Failed to update transaction.")
+			return
+		}
+		resultText = "✅ Transaction allowed."
+	case "block_and_report":
+		if err := ts.DB.Model(&models.Transaction{}).Where("id = ?", pending.TransactionID).Update("status", "blocked").Error; err != nil {
+			ts.answerCallbackQuery(callback.ID, "Failed to update transaction.")
+			return
+		}
+		report := models.Report{
+			ReportedAddress: pending.CounterParty,
+			ReporterAddress: pending.WalletAddress,
+			Category:        "scam",
+			Description:     "Reported from a Telegram security alert",
+			CreatedAt:       time.Now(),
+			Status:          "pending",
+		}
+		if err := ts.DB.Create(&report).Error; err != nil {
+			ts.answerCallbackQuery(callback.ID, "Failed to file report.")
+			return
+		}
+		resultText = "🚫 Transaction blocked and address reported."
+	case "always_allow_sender":
+		allowlist := models.AddressAllowlistEntry{WalletAddress: pending.WalletAddress, AllowedAddress: pending.CounterParty}
+		if err := ts.DB.Where("wallet_address = ? AND allowed_address = ?", allowlist.WalletAddress, allowlist.AllowedAddress).
+			FirstOrCreate(&allowlist).Error; err != nil {
+			ts.answerCallbackQuery(callback.ID, "Failed to update allowlist.")
+			return
+		}
+		if err := ts.DB.Model(&models.Transaction{}).Where("id = ?", pending.TransactionID).Update("status", "safe").Error; err != nil {
+			ts.answerCallbackQuery(callback.ID, "Failed to update transaction.")
+			return
+		}
+		resultText = "⭐ " + pending.CounterParty + " will always be allowed for this wallet."
+	default:
+		ts.answerCallbackQuery(callback.ID, "Unknown action.")
+		return
+	}
+
+	pending.Used = true
+	ts.DB.Save(&pending)
+
+	ts.answerCallbackQuery(callback.ID, resultText)
+	ts.editMessageText(callback.Message.Chat.ID, callback.Message.MessageID, callback.Message.Text+"\n\n"+resultText)
+}
+
 // processMessage handles incoming Telegram messages
 func (ts *TelegramService) processMessage(message *TelegramMessage) {
+	chatIDStr := fmt.Sprintf("%d", message.Chat.ID)
+
+	// A non-command reply while /block or /report is in progress continues
+	// that flow instead of being ignored.
+	if !strings.HasPrefix(message.Text, "/") {
+		var state models.ChatConversationState
+		if err := ts.DB.Where("chat_id = ?", chatIDStr).First(&state).Error; err == nil {
+			ts.handleConversationStep(message, &state)
+			return
+		}
+	}
+
 	// Process commands
 	if strings.HasPrefix(message.Text, "/") {
 		cmd := strings.Split(message.Text, " ")
@@ -218,50 +596,27 @@ func (ts *TelegramService) processMessage(message *TelegramMessage) {
 
 			ts.SendMessage(message.Chat.ID, welcomeMessage)
 		case "/link":
-			if len(cmd) < 2 {
-				ts.SendMessage(message.Chat.ID, "Please provide your wallet address: /link YOUR_WALLET_ADDRESS")
-				return
-			}
-
-			walletAddr := cmd[1]
-			chatID := fmt.Sprintf("%d", message.Chat.ID)
-
-			// Get user details
-			userName := ""
-			firstName := ""
-			lastName := ""
-
-			if message.From != nil {
-				userName = message.From.Username
-				firstName = message.From.FirstName
-				lastName = message.From.LastName
-			}
-
-			if err := ts.LinkWallet(chatID, walletAddr, userName, firstName, lastName); err != nil {
-				log.Printf("Error linking wallet: %v", err)
-				ts.SendMessage(message.Chat.ID, "❌ Failed to link your wallet. Please try again later.")
-				return
-			}
-
-			successMsg := fmt.Sprintf("✅ Successfully linked your Telegram account to wallet %s!\n\n"+
-				"You will now receive security alerts and notifications for this wallet.",
-				walletAddr)
-			ts.SendMessage(message.Chat.ID, successMsg)
+			// /link WALLET_ADDRESS used to bind a wallet on the unverified
+			// say-so of the chat command alone. That's no longer accepted:
+			// linking now requires a wallet signature proving ownership, via
+			// POST /api/auth/telegram/verify + POST /api/telegram/link-session.
+			ts.SendMessage(message.Chat.ID, "🔒 Linking a wallet from chat is no longer supported directly.\n\n"+
+				"Open the app, sign in with Telegram, then confirm the link from your connected wallet.")
 
 		case "/help":
 			helpMessage := "📚 <b>Available Commands</b>\n\n" +
 				"/start - Welcome message and bot introduction\n" +
 				"/link YOUR_WALLET_ADDRESS - Connect your wallet to receive notifications\n" +
 				"/status - Check your current security status\n" +
-				"/block ID - Block a suspicious transaction\n" +
-				"/report ADDRESS - Report a scam address\n" +
+				"/block [ID] - Block a suspicious transaction\n" +
+				"/report [ADDRESS] - Report a scam address\n" +
+				"/cancel - Cancel an in-progress /block or /report\n" +
 				"/help - Show this help message"
 
 			ts.SendMessage(message.Chat.ID, helpMessage)
 		case "/status":
 			// Check if user has linked wallet
 			var mappings []models.TelegramMapping
-			chatIDStr := fmt.Sprintf("%d", message.Chat.ID)
 			result := ts.DB.Where("chat_id = ? AND is_active = ?", chatIDStr, true).Find(&mappings)
 
 			if result.Error != nil || result.RowsAffected == 0 {
@@ -283,18 +638,196 @@ func (ts *TelegramService) processMessage(message *TelegramMessage) {
 			statusMessage += "\nYour wallet is currently protected by UnhackableWallet security features."
 
 			ts.SendMessage(message.Chat.ID, statusMessage)
+		case "/block":
+			if len(cmd) >= 2 {
+				txID, err := strconv.ParseUint(cmd[1], 10, 64)
+				if err != nil {
+					ts.SendMessage(message.Chat.ID, "That doesn't look like a transaction ID.")
+					return
+				}
+				if err := ts.blockTransaction(chatIDStr, uint(txID)); err != nil {
+					ts.SendMessage(message.Chat.ID, "❌ "+err.Error())
+					return
+				}
+				ts.SendMessage(message.Chat.ID, fmt.Sprintf("🚫 Transaction %d has been blocked.", txID))
+				return
+			}
+			ts.startConversation(chatIDStr, "block", "awaiting_tx_id")
+			ts.SendMessage(message.Chat.ID, "Which transaction ID would you like to block? Reply with the ID, or /cancel.")
+		case "/report":
+			if len(cmd) >= 2 {
+				ctxJSON, _ := json.Marshal(map[string]string{"address": cmd[1]})
+				ts.setConversation(chatIDStr, "report", "awaiting_category", string(ctxJSON))
+				ts.SendMessage(message.Chat.ID, "What category is this? (phishing, scam, fraud, other)")
+				return
+			}
+			ts.startConversation(chatIDStr, "report", "awaiting_address")
+			ts.SendMessage(message.Chat.ID, "What address would you like to report? Reply with the address, or /cancel.")
+		case "/cancel":
+			ts.endConversation(chatIDStr)
+			ts.SendMessage(message.Chat.ID, "Cancelled.")
+		}
+	}
+}
+
+// startConversation begins a fresh multi-step command flow for chatID,
+// discarding any flow already in progress for it.
+func (ts *TelegramService) startConversation(chatID, flow, step string) {
+	ts.setConversation(chatID, flow, step, "{}")
+}
+
+// setConversation replaces chatID's conversation state outright, used both
+// to start a flow and to seed it with context already gathered from an
+// inline command argument (e.g. "/report 0xabc...").
+func (ts *TelegramService) setConversation(chatID, flow, step, context string) {
+	ts.DB.Where("chat_id = ?", chatID).Delete(&models.ChatConversationState{})
+	ts.DB.Create(&models.ChatConversationState{ChatID: chatID, Flow: flow, Step: step, Context: context, UpdatedAt: time.Now()})
+}
+
+// endConversation clears chatID's in-progress flow, if any.
+func (ts *TelegramService) endConversation(chatID string) {
+	ts.DB.Where("chat_id = ?", chatID).Delete(&models.ChatConversationState{})
+}
+
+// handleConversationStep advances an in-progress /block or /report flow by
+// one step, using message.Text as the user's reply to the previous prompt.
+func (ts *TelegramService) handleConversationStep(message *TelegramMessage, state *models.ChatConversationState) {
+	chatID := fmt.Sprintf("%d", message.Chat.ID)
+	text := strings.TrimSpace(message.Text)
+
+	switch state.Flow {
+	case "block":
+		txID, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			ts.SendMessage(message.Chat.ID, "That doesn't look like a transaction ID. Please reply with a numeric ID, or /cancel.")
+			return
+		}
+		if err := ts.blockTransaction(chatID, uint(txID)); err != nil {
+			ts.SendMessage(message.Chat.ID, "❌ "+err.Error())
+		} else {
+			ts.SendMessage(message.Chat.ID, fmt.Sprintf("🚫 Transaction %d has been blocked.", txID))
 		}
+		ts.endConversation(chatID)
+	case "report":
+		ts.handleReportStep(message, state, text)
+	default:
+		ts.endConversation(chatID)
 	}
 }
 
-// SetWebhook configures the webhook URL for the Telegram bot
+// handleReportStep advances the /report flow, which gathers address,
+// category, then description across three replies before filing the report.
+func (ts *TelegramService) handleReportStep(message *TelegramMessage, state *models.ChatConversationState, text string) {
+	chatID := fmt.Sprintf("%d", message.Chat.ID)
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(state.Context), &fields); err != nil || fields == nil {
+		fields = map[string]string{}
+	}
+
+	switch state.Step {
+	case "awaiting_address":
+		fields["address"] = text
+		contextJSON, _ := json.Marshal(fields)
+		ts.setConversation(chatID, "report", "awaiting_category", string(contextJSON))
+		ts.SendMessage(message.Chat.ID, "What category is this? (phishing, scam, fraud, other)")
+	case "awaiting_category":
+		fields["category"] = text
+		contextJSON, _ := json.Marshal(fields)
+		ts.setConversation(chatID, "report", "awaiting_description", string(contextJSON))
+		ts.SendMessage(message.Chat.ID, "Briefly describe what happened.")
+	case "awaiting_description":
+		fields["description"] = text
+		if err := ts.reportAddress(chatID, fields["address"], fields["category"], fields["description"]); err != nil {
+			ts.SendMessage(message.Chat.ID, "❌ "+err.Error())
+		} else {
+			ts.SendMessage(message.Chat.ID, "🚨 Report filed for "+fields["address"]+". Thank you.")
+		}
+		ts.endConversation(chatID)
+	default:
+		ts.endConversation(chatID)
+	}
+}
+
+// blockTransaction marks a transaction blocked on behalf of the wallet
+// linked to chatID, used by both the single-shot "/block ID" command and
+// the multi-step flow.
+func (ts *TelegramService) blockTransaction(chatID string, transactionID uint) error {
+	var mapping models.TelegramMapping
+	if err := ts.DB.Where("chat_id = ? AND is_active = ?", chatID, true).First(&mapping).Error; err != nil {
+		return fmt.Errorf("you don't have a linked wallet")
+	}
+
+	result := ts.DB.Model(&models.Transaction{}).
+		Where("id = ? AND from_address = ?", transactionID, mapping.WalletAddress).
+		Update("status", "blocked")
+	if result.Error != nil {
+		return fmt.Errorf("failed to block transaction")
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("transaction not found for your linked wallet")
+	}
+	return nil
+}
+
+// reportAddress files a scam report on behalf of the wallet linked to
+// chatID, used by both "/report ADDRESS" and the multi-step flow.
+func (ts *TelegramService) reportAddress(chatID, reportedAddress, category, description string) error {
+	var mapping models.TelegramMapping
+	if err := ts.DB.Where("chat_id = ? AND is_active = ?", chatID, true).First(&mapping).Error; err != nil {
+		return fmt.Errorf("you don't have a linked wallet")
+	}
+
+	report := models.Report{
+		ReportedAddress: reportedAddress,
+		ReporterAddress: mapping.WalletAddress,
+		Category:        category,
+		Description:     description,
+		CreatedAt:       time.Now(),
+		Status:          "pending",
+	}
+	return ts.DB.Create(&report).Error
+}
+
+// webhookSecretConfigKey and webhookURLConfigKey are the Config table keys
+// SetWebhook persists its secret_token and URL under, so RotateWebhookSecret
+// and verifyWebhookRequest can recover them across restarts.
+const (
+	webhookSecretConfigKey = "telegram_webhook_secret"
+	webhookURLConfigKey    = "telegram_webhook_url"
+)
+
+// processedUpdateTTL bounds how long an UpdateID is remembered for
+// deduplicating against Telegram's at-least-once delivery retries.
+const processedUpdateTTL = 24 * time.Hour
+
+// SetWebhook configures the webhook URL for the Telegram bot. A fresh
+// secret_token is generated and persisted alongside the URL, and sent to
+// Telegram so every subsequent delivery can be authenticated by
+// verifyWebhookRequest.
 func (ts *TelegramService) SetWebhook(webhookURL string) error {
 	if ts.Token == "" {
 		return fmt.Errorf("telegram token not configured")
 	}
 
-	url := fmt.Sprintf("%s/setWebhook?url=%s", ts.BaseURL, webhookURL)
-	resp, err := http.Get(url)
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	if err := ts.setConfigValue(webhookURLConfigKey, webhookURL); err != nil {
+		return fmt.Errorf("failed to persist webhook url: %w", err)
+	}
+	if err := ts.setConfigValue(webhookSecretConfigKey, secret); err != nil {
+		return fmt.Errorf("failed to persist webhook secret: %w", err)
+	}
+
+	payload := map[string]string{"url": webhookURL, "secret_token": secret}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal setWebhook payload: %w", err)
+	}
+
+	resp, err := http.Post(fmt.Sprintf("%s/setWebhook", ts.BaseURL), "application/json", strings.NewReader(string(jsonPayload)))
 	if err != nil {
 		return fmt.Errorf("failed to set webhook: %w", err)
 	}
@@ -308,6 +841,44 @@ func (ts *TelegramService) SetWebhook(webhookURL string) error {
 	return nil
 }
 
+// RotateWebhookSecret re-registers the webhook most recently set by
+// SetWebhook under a freshly generated secret_token, invalidating the
+// previous one. Intended to be callable from the /api/admin group so an
+// operator can recover from a leaked secret without redeploying.
+func (ts *TelegramService) RotateWebhookSecret() error {
+	var row models.Config
+	if err := ts.DB.Where("key = ?", webhookURLConfigKey).First(&row).Error; err != nil {
+		return fmt.Errorf("no webhook URL on record; call SetWebhook first: %w", err)
+	}
+	return ts.SetWebhook(row.Value)
+}
+
+// generateWebhookSecret produces the random value sent to Telegram as
+// secret_token and checked against X-Telegram-Bot-Api-Secret-Token.
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// setConfigValue upserts a single Config row by key.
+func (ts *TelegramService) setConfigValue(key, value string) error {
+	return ts.DB.Transaction(func(tx *gorm.DB) error {
+		var row models.Config
+		result := tx.Where("key = ?", key).First(&row)
+		if result.Error == gorm.ErrRecordNotFound {
+			return tx.Create(&models.Config{Key: key, Value: value}).Error
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		row.Value = value
+		return tx.Save(&row).Error
+	})
+}
+
 // NotifyAdmin sends a message to the admin chat
 func (ts *TelegramService) NotifyAdmin(text string) error {
 	// Get admin chat ID from environment variable
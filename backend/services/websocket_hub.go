@@ -0,0 +1,318 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"Wallet/backend/models"
+)
+
+// wsSendBufferSize bounds each client's outbound queue; enqueue drops the
+// oldest queued message rather than blocking when a slow client falls
+// behind, so one laggy connection can't back up delivery to everyone else.
+const wsSendBufferSize = 32
+
+// wsHeartbeatInterval is how often the hub pings each connected client.
+const wsHeartbeatInterval = 30 * time.Second
+
+// wsTextMessageType and wsPingMessageType mirror
+// github.com/gorilla/websocket's TextMessage/PingMessage constants, kept
+// local so this package doesn't need to import gorilla/websocket itself.
+const (
+	wsTextMessageType = 1
+	wsPingMessageType = 9
+)
+
+// WebsocketConn is the subset of *websocket.Conn (gorilla/websocket) the hub
+// needs. Keeping this package free of a direct gorilla/websocket import lets
+// the handlers package own the HTTP upgrade while WebsocketHub stays
+// transport-agnostic; *websocket.Conn already satisfies this interface
+// structurally, so no adapter is needed at the call site.
+type WebsocketConn interface {
+	WriteMessage(messageType int, data []byte) error
+	ReadMessage() (messageType int, p []byte, err error)
+	Close() error
+}
+
+// AnalyzeFunc lets a connected client drive transaction analysis via the
+// "analyze" JSON-RPC method without WebsocketHub importing the handlers
+// package. Wired in by routes.go from FirewallHandler.Analyze.
+type AnalyzeFunc func(tx models.Transaction) (status string, risk float64, err error)
+
+// jsonRPCRequest is a JSON-RPC 2.0 request frame, used both for the
+// subscribe/unsubscribe/analyze calls a client may send and for the
+// TransactionAnalyzed/SecurityAlertRaised/ReportCreated notifications the
+// hub pushes (as a notification, Method is the event type and ID is absent).
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      interface{}     `json:"id,omitempty"`
+}
+
+// jsonRPCResponse is the corresponding JSON-RPC 2.0 response frame.
+type jsonRPCResponse struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *jsonRPCError `json:"error,omitempty"`
+	ID      interface{}   `json:"id,omitempty"`
+}
+
+// jsonRPCError is the "error" member of a jsonRPCResponse.
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// wsClient is one connected socket and the set of wallet addresses it's
+// subscribed to. A client is always subscribed to authAddress, the wallet
+// address it authenticated as (see Register) - the "subscribe"/"unsubscribe"
+// JSON-RPC methods below are scoped to that same address, since a client has
+// no business reading another wallet's event stream.
+type wsClient struct {
+	conn WebsocketConn
+
+	authAddress string
+
+	mu            sync.Mutex
+	subscriptions map[string]bool
+
+	send chan []byte
+}
+
+// WebsocketHub fans TransactionAnalyzed, SecurityAlertRaised, and
+// ReportCreated events from an EventBus out to connected WebSocket clients,
+// mirroring the Telegram notification path for browser/mobile frontends
+// that want live updates instead of polling GetStats/GetTransactions. It
+// also accepts JSON-RPC 2.0 "subscribe"/"unsubscribe"/"analyze" calls over
+// the same connection.
+type WebsocketHub struct {
+	mu      sync.RWMutex
+	clients map[*wsClient]bool
+	analyze AnalyzeFunc
+
+	droppedMessages int64
+}
+
+// NewWebsocketHub creates a hub and subscribes it to the three event types
+// it forwards, on bus.
+func NewWebsocketHub(bus *EventBus) *WebsocketHub {
+	hub := &WebsocketHub{clients: make(map[*wsClient]bool)}
+	bus.Subscribe(EventTransactionAnalyzed, hub.forward(EventTransactionAnalyzed))
+	bus.Subscribe(EventSecurityAlertRaised, hub.forward(EventSecurityAlertRaised))
+	bus.Subscribe(EventReportCreated, hub.forward(EventReportCreated))
+	return hub
+}
+
+// SetAnalyzeFunc wires the "analyze" JSON-RPC method to fn.
+func (h *WebsocketHub) SetAnalyzeFunc(fn AnalyzeFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.analyze = fn
+}
+
+// DroppedMessageCount returns how many outbound messages have been dropped
+// across all clients due to backpressure, for FirewallHandler.GetAdminStats.
+func (h *WebsocketHub) DroppedMessageCount() int64 {
+	return atomic.LoadInt64(&h.droppedMessages)
+}
+
+// forward returns an EventHandler that pushes matching events, framed as a
+// JSON-RPC 2.0 notification whose Method is eventType, to every client
+// subscribed to event.WalletAddress.
+func (h *WebsocketHub) forward(eventType string) EventHandler {
+	return func(event Event) {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			log.Printf("WebsocketHub: failed to marshal %s payload: %v", eventType, err)
+			return
+		}
+		data, err := json.Marshal(jsonRPCRequest{JSONRPC: "2.0", Method: eventType, Params: payload})
+		if err != nil {
+			log.Printf("WebsocketHub: failed to marshal %s notification: %v", eventType, err)
+			return
+		}
+
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		for client := range h.clients {
+			if client.isSubscribedTo(event.WalletAddress) {
+				h.enqueue(client, data)
+			}
+		}
+	}
+}
+
+// enqueue pushes data onto client's send buffer, dropping the oldest queued
+// message (and counting it) if the buffer is already full, rather than
+// blocking the hub on one slow client.
+func (h *WebsocketHub) enqueue(client *wsClient, data []byte) {
+	select {
+	case client.send <- data:
+		return
+	default:
+	}
+
+	select {
+	case <-client.send:
+		atomic.AddInt64(&h.droppedMessages, 1)
+	default:
+	}
+
+	select {
+	case client.send <- data:
+	default:
+	}
+}
+
+// Register adds a newly upgraded connection, authenticated as walletAddress,
+// to the hub, and runs its write/read pumps. It blocks until the connection
+// closes, so callers should invoke it from the request goroutine handling
+// the upgrade.
+func (h *WebsocketHub) Register(conn WebsocketConn, walletAddress string) {
+	client := &wsClient{
+		conn:          conn,
+		authAddress:   walletAddress,
+		subscriptions: map[string]bool{walletAddress: true},
+		send:          make(chan []byte, wsSendBufferSize),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go h.writePump(client, done)
+
+	h.readPump(client)
+
+	close(done)
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+	conn.Close()
+}
+
+// writePump serializes all writes to client.conn: queued event
+// notifications and periodic heartbeat pings.
+func (h *WebsocketHub) writePump(client *wsClient, done <-chan struct{}) {
+	ticker := time.NewTicker(wsHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-client.send:
+			if err := client.conn.WriteMessage(wsTextMessageType, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := client.conn.WriteMessage(wsPingMessageType, nil); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// readPump processes inbound JSON-RPC frames (subscribe/unsubscribe/analyze)
+// until the connection errors or closes.
+func (h *WebsocketHub) readPump(client *wsClient) {
+	for {
+		_, data, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req jsonRPCRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			h.enqueue(client, encodeRPCError(nil, "invalid JSON-RPC frame"))
+			continue
+		}
+		h.handleRequest(client, req)
+	}
+}
+
+func (h *WebsocketHub) handleRequest(client *wsClient, req jsonRPCRequest) {
+	switch req.Method {
+	case "subscribe", "unsubscribe":
+		var params struct {
+			WalletAddress string `json:"walletAddress"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.WalletAddress == "" {
+			h.enqueue(client, encodeRPCError(req.ID, req.Method+" requires a walletAddress param"))
+			return
+		}
+		// A client may only (un)subscribe to the wallet address it
+		// authenticated as - never a client-supplied address, which would
+		// let any connected user read another wallet's event stream.
+		if !strings.EqualFold(params.WalletAddress, client.authAddress) {
+			h.enqueue(client, encodeRPCError(req.ID, "can only subscribe to your own walletAddress"))
+			return
+		}
+		if req.Method == "subscribe" {
+			client.subscribe(client.authAddress)
+			h.enqueue(client, encodeRPCResult(req.ID, "subscribed"))
+		} else {
+			client.unsubscribe(client.authAddress)
+			h.enqueue(client, encodeRPCResult(req.ID, "unsubscribed"))
+		}
+
+	case "analyze":
+		h.mu.RLock()
+		analyze := h.analyze
+		h.mu.RUnlock()
+		if analyze == nil {
+			h.enqueue(client, encodeRPCError(req.ID, "analyze is not available"))
+			return
+		}
+
+		var tx models.Transaction
+		if err := json.Unmarshal(req.Params, &tx); err != nil {
+			h.enqueue(client, encodeRPCError(req.ID, "invalid transaction payload"))
+			return
+		}
+
+		status, risk, err := analyze(tx)
+		if err != nil {
+			h.enqueue(client, encodeRPCError(req.ID, err.Error()))
+			return
+		}
+		h.enqueue(client, encodeRPCResult(req.ID, map[string]interface{}{"status": status, "risk": risk}))
+
+	default:
+		h.enqueue(client, encodeRPCError(req.ID, "unknown method: "+req.Method))
+	}
+}
+
+func (c *wsClient) isSubscribedTo(walletAddress string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscriptions[walletAddress]
+}
+
+func (c *wsClient) subscribe(walletAddress string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscriptions[walletAddress] = true
+}
+
+func (c *wsClient) unsubscribe(walletAddress string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.subscriptions, walletAddress)
+}
+
+func encodeRPCResult(id interface{}, result interface{}) []byte {
+	data, _ := json.Marshal(jsonRPCResponse{JSONRPC: "2.0", Result: result, ID: id})
+	return data
+}
+
+func encodeRPCError(id interface{}, message string) []byte {
+	data, _ := json.Marshal(jsonRPCResponse{JSONRPC: "2.0", Error: &jsonRPCError{Code: -32000, Message: message}, ID: id})
+	return data
+}
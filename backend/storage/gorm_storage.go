@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"Wallet/backend/models"
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// GormStorage implements UserStore, SessionStore, DAOStore, and
+// VerificationLogStore on top of a *gorm.DB. The same implementation backs
+// both the Postgres and SQLite drivers - they differ only in how the
+// underlying *gorm.DB is opened, not in how these methods query it.
+type GormStorage struct {
+	db *gorm.DB
+}
+
+// NewGormStorage wraps an already-connected *gorm.DB, e.g. one opened by
+// config.InitDB for callers that haven't migrated off *gorm.DB directly yet.
+func NewGormStorage(db *gorm.DB) *GormStorage {
+	return &GormStorage{db: db}
+}
+
+// NewPostgresStorage opens a Postgres connection at dsn and returns a
+// GormStorage backed by it.
+func NewPostgresStorage(dsn string) (*GormStorage, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{PrepareStmt: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	return NewGormStorage(db), nil
+}
+
+// NewSQLiteStorage opens (creating if needed) a SQLite database file at
+// path, for local development or embedded deployments that don't want to
+// stand up a Postgres instance.
+func NewSQLiteStorage(path string) (*GormStorage, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	return NewGormStorage(db), nil
+}
+
+// DB exposes the underlying *gorm.DB for callers not yet migrated onto the
+// store interfaces (most of this codebase, for now - see package doc).
+func (s *GormStorage) DB() *gorm.DB {
+	return s.db
+}
+
+// --- UserStore ---
+
+func (s *GormStorage) GetReputation(address string) (*models.AddressReputation, error) {
+	var rep models.AddressReputation
+	if err := s.db.Where("address = ?", address).First(&rep).Error; err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+func (s *GormStorage) UpsertReputation(rep *models.AddressReputation) error {
+	return s.db.Save(rep).Error
+}
+
+func (s *GormStorage) GetNonce(address string) (*models.Nonce, error) {
+	var nonce models.Nonce
+	if err := s.db.Where("address = ?", address).First(&nonce).Error; err != nil {
+		return nil, err
+	}
+	return &nonce, nil
+}
+
+func (s *GormStorage) ConsumeNonce(address, nonce string) error {
+	return s.db.Where("address = ? AND nonce = ?", address, nonce).Delete(&models.Nonce{}).Error
+}
+
+// --- SessionStore ---
+
+func (s *GormStorage) GetActiveSession(userAddress string) (*models.CivicAuthSession, error) {
+	var session models.CivicAuthSession
+	if err := s.db.Where("user_address = ? AND token_expiry > ?", userAddress, time.Now()).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *GormStorage) GetSessionByGatepass(userAddress, gatepass string) (*models.CivicAuthSession, error) {
+	var session models.CivicAuthSession
+	if err := s.db.Where("user_address = ? AND gate_pass = ?", userAddress, gatepass).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *GormStorage) SaveSession(session *models.CivicAuthSession) error {
+	return s.db.Save(session).Error
+}
+
+func (s *GormStorage) CountOtherDevices(userAddress, deviceHash string) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.CivicAuthSession{}).
+		Where("user_address = ? AND device_hash != ?", userAddress, deviceHash).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *GormStorage) GetDeviceCredential(fingerprint string) (*models.DeviceCredential, error) {
+	var cred models.DeviceCredential
+	if err := s.db.Where("fingerprint = ?", fingerprint).First(&cred).Error; err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (s *GormStorage) GetDeviceCredentialForUser(userAddress, fingerprint string) (*models.DeviceCredential, error) {
+	var cred models.DeviceCredential
+	if err := s.db.Where("fingerprint = ? AND user_address = ?", fingerprint, userAddress).First(&cred).Error; err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (s *GormStorage) CreateDeviceCredential(cred *models.DeviceCredential) error {
+	return s.db.Create(cred).Error
+}
+
+func (s *GormStorage) RevokeDeviceCredential(fingerprint string, revokedAt time.Time) (bool, error) {
+	result := s.db.Model(&models.DeviceCredential{}).
+		Where("fingerprint = ? AND revoked_at IS NULL", fingerprint).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *GormStorage) ListRevokedDeviceCredentials() ([]models.DeviceCredential, error) {
+	var creds []models.DeviceCredential
+	err := s.db.Where("revoked_at IS NOT NULL").Find(&creds).Error
+	return creds, err
+}
+
+// --- DAOStore ---
+
+func (s *GormStorage) CreateProposal(p *models.DAOProposal) error {
+	return s.db.Create(p).Error
+}
+
+func (s *GormStorage) GetProposal(id uint) (*models.DAOProposal, error) {
+	var p models.DAOProposal
+	if err := s.db.First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *GormStorage) ListProposals() ([]models.DAOProposal, error) {
+	var proposals []models.DAOProposal
+	err := s.db.Find(&proposals).Error
+	return proposals, err
+}
+
+func (s *GormStorage) SaveProposal(p *models.DAOProposal) error {
+	return s.db.Save(p).Error
+}
+
+func (s *GormStorage) CreateVote(v *models.DAOVote) error {
+	return s.db.Create(v).Error
+}
+
+func (s *GormStorage) ListVotes(proposalID uint) ([]models.DAOVote, error) {
+	var votes []models.DAOVote
+	err := s.db.Where("proposal_id = ?", proposalID).Find(&votes).Error
+	return votes, err
+}
+
+// --- VerificationLogStore ---
+
+func (s *GormStorage) CreateLog(entry *models.CivicVerificationLog) error {
+	return s.db.Create(entry).Error
+}
+
+func (s *GormStorage) CountRecentAttempts(userAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.CivicVerificationLog{}).
+		Where("user_address = ? AND created_at > ?", userAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *GormStorage) LastGeoPoint(userAddress string) (GeoPoint, bool, error) {
+	var entry models.CivicVerificationLog
+	err := s.db.Model(&models.CivicVerificationLog{}).
+		Where("user_address = ? AND geo_country != ''", userAddress).
+		Order("created_at desc").
+		Limit(1).
+		First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return GeoPoint{}, false, nil
+	}
+	if err != nil {
+		return GeoPoint{}, false, err
+	}
+	return GeoPoint{
+		Country:    entry.GeoCountry,
+		ASN:        entry.GeoASN,
+		Latitude:   entry.GeoLatitude,
+		Longitude:  entry.GeoLongitude,
+		RecordedAt: entry.CreatedAt,
+	}, true, nil
+}
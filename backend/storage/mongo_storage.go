@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"Wallet/backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoOpTimeout bounds each individual Mongo operation below; none of
+// these methods take a caller context since they implement the same
+// context-free interfaces GormStorage does.
+const mongoOpTimeout = 10 * time.Second
+
+// MongoStorage implements UserStore, SessionStore, DAOStore, and
+// VerificationLogStore against a MongoDB database, for deployments that
+// already run Mongo and don't want to stand up Postgres/SQLite just for
+// this service.
+type MongoStorage struct {
+	db *mongo.Database
+}
+
+// NewMongoStorage connects to uri and returns a MongoStorage backed by
+// database dbName.
+func NewMongoStorage(uri, dbName string) (*MongoStorage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoOpTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mongo: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping mongo: %w", err)
+	}
+
+	return &MongoStorage{db: client.Database(dbName)}, nil
+}
+
+func (s *MongoStorage) reputations() *mongo.Collection {
+	return s.db.Collection("address_reputations")
+}
+func (s *MongoStorage) nonces() *mongo.Collection { return s.db.Collection("nonces") }
+func (s *MongoStorage) sessions() *mongo.Collection {
+	return s.db.Collection("civic_auth_sessions")
+}
+func (s *MongoStorage) deviceCredentials() *mongo.Collection {
+	return s.db.Collection("device_credentials")
+}
+func (s *MongoStorage) proposals() *mongo.Collection { return s.db.Collection("dao_proposals") }
+func (s *MongoStorage) votes() *mongo.Collection     { return s.db.Collection("dao_votes") }
+func (s *MongoStorage) verificationLogs() *mongo.Collection {
+	return s.db.Collection("civic_verification_logs")
+}
+
+func opCtx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), mongoOpTimeout)
+}
+
+// --- UserStore ---
+
+func (s *MongoStorage) GetReputation(address string) (*models.AddressReputation, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var rep models.AddressReputation
+	if err := s.reputations().FindOne(ctx, bson.M{"address": address}).Decode(&rep); err != nil {
+		return nil, err
+	}
+	return &rep, nil
+}
+
+func (s *MongoStorage) UpsertReputation(rep *models.AddressReputation) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.reputations().ReplaceOne(ctx, bson.M{"address": rep.Address}, rep, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStorage) GetNonce(address string) (*models.Nonce, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var nonce models.Nonce
+	if err := s.nonces().FindOne(ctx, bson.M{"address": address}).Decode(&nonce); err != nil {
+		return nil, err
+	}
+	return &nonce, nil
+}
+
+func (s *MongoStorage) ConsumeNonce(address, nonce string) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.nonces().DeleteOne(ctx, bson.M{"address": address, "nonce": nonce})
+	return err
+}
+
+// --- SessionStore ---
+
+func (s *MongoStorage) GetActiveSession(userAddress string) (*models.CivicAuthSession, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var session models.CivicAuthSession
+	filter := bson.M{"user_address": userAddress, "token_expiry": bson.M{"$gt": time.Now()}}
+	if err := s.sessions().FindOne(ctx, filter).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *MongoStorage) GetSessionByGatepass(userAddress, gatepass string) (*models.CivicAuthSession, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var session models.CivicAuthSession
+	filter := bson.M{"user_address": userAddress, "gate_pass": gatepass}
+	if err := s.sessions().FindOne(ctx, filter).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *MongoStorage) SaveSession(session *models.CivicAuthSession) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.sessions().ReplaceOne(ctx, bson.M{"_id": session.ID}, session, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStorage) CountOtherDevices(userAddress, deviceHash string) (int64, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	filter := bson.M{"user_address": userAddress, "device_hash": bson.M{"$ne": deviceHash}}
+	return s.sessions().CountDocuments(ctx, filter)
+}
+
+func (s *MongoStorage) GetDeviceCredential(fingerprint string) (*models.DeviceCredential, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var cred models.DeviceCredential
+	if err := s.deviceCredentials().FindOne(ctx, bson.M{"fingerprint": fingerprint}).Decode(&cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (s *MongoStorage) GetDeviceCredentialForUser(userAddress, fingerprint string) (*models.DeviceCredential, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var cred models.DeviceCredential
+	filter := bson.M{"fingerprint": fingerprint, "user_address": userAddress}
+	if err := s.deviceCredentials().FindOne(ctx, filter).Decode(&cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+func (s *MongoStorage) CreateDeviceCredential(cred *models.DeviceCredential) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.deviceCredentials().InsertOne(ctx, cred)
+	return err
+}
+
+func (s *MongoStorage) RevokeDeviceCredential(fingerprint string, revokedAt time.Time) (bool, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	filter := bson.M{"fingerprint": fingerprint, "revoked_at": nil}
+	result, err := s.deviceCredentials().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": revokedAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (s *MongoStorage) ListRevokedDeviceCredentials() ([]models.DeviceCredential, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	cursor, err := s.deviceCredentials().Find(ctx, bson.M{"revoked_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var creds []models.DeviceCredential
+	if err := cursor.All(ctx, &creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// --- DAOStore ---
+
+func (s *MongoStorage) CreateProposal(p *models.DAOProposal) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.proposals().InsertOne(ctx, p)
+	return err
+}
+
+func (s *MongoStorage) GetProposal(id uint) (*models.DAOProposal, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	var p models.DAOProposal
+	if err := s.proposals().FindOne(ctx, bson.M{"id": id}).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *MongoStorage) ListProposals() ([]models.DAOProposal, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	cursor, err := s.proposals().Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var proposals []models.DAOProposal
+	if err := cursor.All(ctx, &proposals); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func (s *MongoStorage) SaveProposal(p *models.DAOProposal) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.proposals().ReplaceOne(ctx, bson.M{"id": p.ID}, p, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStorage) CreateVote(v *models.DAOVote) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.votes().InsertOne(ctx, v)
+	return err
+}
+
+func (s *MongoStorage) ListVotes(proposalID uint) ([]models.DAOVote, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	cursor, err := s.votes().Find(ctx, bson.M{"proposal_id": proposalID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var votes []models.DAOVote
+	if err := cursor.All(ctx, &votes); err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// --- VerificationLogStore ---
+
+func (s *MongoStorage) CreateLog(entry *models.CivicVerificationLog) error {
+	ctx, cancel := opCtx()
+	defer cancel()
+	_, err := s.verificationLogs().InsertOne(ctx, entry)
+	return err
+}
+
+func (s *MongoStorage) CountRecentAttempts(userAddress string, since time.Time) (int64, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	filter := bson.M{"user_address": userAddress, "created_at": bson.M{"$gt": since}}
+	return s.verificationLogs().CountDocuments(ctx, filter)
+}
+
+func (s *MongoStorage) LastGeoPoint(userAddress string) (GeoPoint, bool, error) {
+	ctx, cancel := opCtx()
+	defer cancel()
+	filter := bson.M{"user_address": userAddress, "geo_country": bson.M{"$ne": ""}}
+	findOpts := options.FindOne().SetSort(bson.M{"created_at": -1})
+
+	var entry models.CivicVerificationLog
+	if err := s.verificationLogs().FindOne(ctx, filter, findOpts).Decode(&entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return GeoPoint{}, false, nil
+		}
+		return GeoPoint{}, false, err
+	}
+	return GeoPoint{
+		Country:    entry.GeoCountry,
+		ASN:        entry.GeoASN,
+		Latitude:   entry.GeoLatitude,
+		Longitude:  entry.GeoLongitude,
+		RecordedAt: entry.CreatedAt,
+	}, true, nil
+}
@@ -0,0 +1,85 @@
+// Package storage defines the interfaces services depend on for persisting
+// wallet/auth data instead of importing gorm.io/gorm and a concrete driver
+// directly. This lets a service's tests substitute an in-memory fake, and
+// lets a deployment choose a Postgres, SQLite, or MongoDB-backed
+// implementation without touching the services that consume it.
+//
+// Migrating every service onto these interfaces in one pass would be a huge,
+// risky diff; for now services.CivicAuthService (SessionStore/
+// VerificationLogStore) and dao.ProposalExecutor (DAOStore) depend on these
+// interfaces instead of *gorm.DB. Everything else keeps using *gorm.DB
+// directly until it's migrated the same way.
+package storage
+
+import (
+	"Wallet/backend/models"
+	"time"
+)
+
+// UserStore persists wallet-identified records used outside any one auth
+// flow: address reputation and signature-replay nonces.
+type UserStore interface {
+	GetReputation(address string) (*models.AddressReputation, error)
+	UpsertReputation(rep *models.AddressReputation) error
+	GetNonce(address string) (*models.Nonce, error)
+	ConsumeNonce(address, nonce string) error
+}
+
+// SessionStore persists Civic auth sessions and the device certificates
+// issued for the mTLS auth channel.
+type SessionStore interface {
+	GetActiveSession(userAddress string) (*models.CivicAuthSession, error)
+	GetSessionByGatepass(userAddress, gatepass string) (*models.CivicAuthSession, error)
+	SaveSession(session *models.CivicAuthSession) error
+	CountOtherDevices(userAddress, deviceHash string) (int64, error)
+
+	GetDeviceCredential(fingerprint string) (*models.DeviceCredential, error)
+	GetDeviceCredentialForUser(userAddress, fingerprint string) (*models.DeviceCredential, error)
+	CreateDeviceCredential(cred *models.DeviceCredential) error
+	RevokeDeviceCredential(fingerprint string, revokedAt time.Time) (bool, error)
+	ListRevokedDeviceCredentials() ([]models.DeviceCredential, error)
+}
+
+// DAOStore persists DAO proposals and votes.
+type DAOStore interface {
+	CreateProposal(p *models.DAOProposal) error
+	GetProposal(id uint) (*models.DAOProposal, error)
+	ListProposals() ([]models.DAOProposal, error)
+	SaveProposal(p *models.DAOProposal) error
+	CreateVote(v *models.DAOVote) error
+	ListVotes(proposalID uint) ([]models.DAOVote, error)
+}
+
+// GeoPoint is a verification attempt's resolved location, as looked up by
+// services.GeoIPLookup and recorded alongside a CivicVerificationLog row.
+// CivicAuthService.performSecurityChecks compares Country/ASN between
+// consecutive attempts for location_change_detected, and Haversine-distances
+// Latitude/Longitude for impossible_travel.
+type GeoPoint struct {
+	Country    string
+	ASN        string
+	Latitude   float64
+	Longitude  float64
+	RecordedAt time.Time
+}
+
+// VerificationLogStore persists Civic verification attempts, used by
+// CivicAuthService.performSecurityChecks' rapid-attempt and geo-anomaly
+// heuristics.
+type VerificationLogStore interface {
+	CreateLog(entry *models.CivicVerificationLog) error
+	CountRecentAttempts(userAddress string, since time.Time) (int64, error)
+	// LastGeoPoint returns userAddress's most recently logged GeoPoint, or
+	// ok=false if it has none yet.
+	LastGeoPoint(userAddress string) (point GeoPoint, ok bool, err error)
+}
+
+// Storage bundles every store interface a service might need, so a single
+// driver-specific setup (see GormStorage/MongoStorage) can satisfy whichever
+// subset a given service depends on.
+type Storage struct {
+	Users            UserStore
+	Sessions         SessionStore
+	DAO              DAOStore
+	VerificationLogs VerificationLogStore
+}